@@ -0,0 +1,272 @@
+package tcgplayer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// How many chunks are requested at the same time by the batch helpers,
+// unless changed with WithBatchConcurrency
+const defaultBatchConcurrency = 4
+
+// A chunk of ids that could not be retrieved by a batch helper
+type ChunkError struct {
+	Ids []int
+	Err error
+}
+
+// Returned by the batch helpers when one or more chunks fail, listing every
+// failed chunk, so that only their ids need to be requested again. It
+// unwraps to the error of the first failed chunk.
+type BatchError struct {
+	Chunks []ChunkError
+}
+
+func (e *BatchError) Error() string {
+	if len(e.Chunks) == 1 {
+		return e.Chunks[0].Err.Error()
+	}
+	return fmt.Sprintf("%d chunks failed, first error: %s", len(e.Chunks), e.Chunks[0].Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Chunks[0].Err
+}
+
+// Return the ids of all the failed chunks
+func (e *BatchError) Ids() []int {
+	var out []int
+	for _, chunk := range e.Chunks {
+		out = append(out, chunk.Ids...)
+	}
+	return out
+}
+
+// Retrieve details for any number of products, splitting the ids in chunks
+// of MaxIdsInRequest, and passing each completed chunk to fn
+func (tcg *Client) GetAllProductsDetailsFunc(productIds []int, includeSkus bool, fn func([]Product) error) error {
+	return runBatches(tcg, productIds, func(c *Client, ids []int) ([]Product, error) {
+		return c.GetProductsDetails(ids, includeSkus)
+	}, fn)
+}
+
+// Retrieve details for any number of products, in the same order as the
+// input ids, each product being returned once even if its id is repeated.
+// On error, the results of the chunks completed so far are returned as well.
+func (tcg *Client) GetAllProductsDetails(productIds []int, includeSkus bool) ([]Product, error) {
+	var out []Product
+	err := tcg.GetAllProductsDetailsFunc(productIds, includeSkus, func(products []Product) error {
+		out = append(out, products...)
+		return nil
+	})
+	sortByIds(out, productIds, func(item Product) int {
+		return item.ProductId
+	})
+	return out, err
+}
+
+// Retrieve details for any number of products, indexed by product id, so
+// that any id missing from the map was not returned by the API.
+// On error, the results of the chunks completed so far are returned as well.
+func (tcg *Client) GetProductsDetailsMap(productIds []int, includeSkus bool) (map[int]Product, error) {
+	out := make(map[int]Product, len(productIds))
+	err := tcg.GetAllProductsDetailsFunc(productIds, includeSkus, func(products []Product) error {
+		for _, product := range products {
+			out[product.ProductId] = product
+		}
+		return nil
+	})
+	return out, err
+}
+
+// Retrieve market prices for any number of products, splitting the ids in
+// chunks of MaxIdsInRequest, and passing each completed chunk to fn
+func (tcg *Client) GetAllMarketPricesByProductsFunc(productIds []int, fn func([]ProductPriceSet) error) error {
+	return runBatches(tcg, productIds, (*Client).GetMarketPricesByProducts, fn)
+}
+
+// Retrieve market prices for any number of products, in the same order as the
+// input ids. A product may have more than one price set, one per subtype such
+// as Normal or Foil, which are kept next to each other.
+// On error, the results of the chunks completed so far are returned as well.
+func (tcg *Client) GetAllMarketPricesByProducts(productIds []int) ([]ProductPriceSet, error) {
+	var out []ProductPriceSet
+	err := tcg.GetAllMarketPricesByProductsFunc(productIds, func(prices []ProductPriceSet) error {
+		out = append(out, prices...)
+		return nil
+	})
+	sortByIds(out, productIds, func(item ProductPriceSet) int {
+		return item.ProductId
+	})
+	return out, err
+}
+
+// Retrieve market prices for any number of SKUs, splitting the ids in chunks
+// of MaxIdsInRequest, and passing each completed chunk to fn
+func (tcg *Client) GetAllMarketPricesBySKUsFunc(skuIds []int, fn func([]SKUPriceSet) error) error {
+	return runBatches(tcg, skuIds, (*Client).GetMarketPricesBySKUs, fn)
+}
+
+// Retrieve market prices for any number of SKUs, in the same order as the
+// input ids.
+// On error, the results of the chunks completed so far are returned as well.
+func (tcg *Client) GetAllMarketPricesBySKUs(skuIds []int) ([]SKUPriceSet, error) {
+	var out []SKUPriceSet
+	err := tcg.GetAllMarketPricesBySKUsFunc(skuIds, func(prices []SKUPriceSet) error {
+		out = append(out, prices...)
+		return nil
+	})
+	sortByIds(out, skuIds, func(item SKUPriceSet) int {
+		return item.SkuId
+	})
+	return out, err
+}
+
+// Split ids in chunks, fetch them concurrently, and pass the results of each
+// chunk to fn as soon as they are available, one chunk at a time.
+// A failed chunk does not stop the others, and a BatchError listing every
+// failed chunk is returned at the end, unless WithFailFast is set, in which
+// case any chunk still in progress or not yet requested is cancelled. An
+// error from fn always cancels any remaining chunk, and is returned as is.
+// The Client context bounds the whole batch, including any retry, so that a
+// deadline stops every remaining chunk.
+// Repeated ids are only requested once.
+func runBatches[T any](tcg *Client, ids []int, fetch func(*Client, []int) ([]T, error), fn func([]T) error) error {
+	chunks := Chunk(uniqueIds(ids), MaxIdsInRequest)
+
+	// Use a dedicated context to be able to cancel in-flight requests
+	ctx, cancel := context.WithCancel(tcg.ctx)
+	defer cancel()
+	batchClient := tcg.withContext(ctx)
+
+	workers := tcg.batchConcurrency
+	if workers <= 0 {
+		workers = defaultBatchConcurrency
+	}
+	if workers > len(chunks) {
+		workers = len(chunks)
+	}
+
+	jobs := make(chan []int)
+	abort := make(chan struct{})
+	var aborted bool
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	var fnErr error
+	var failed []ChunkError
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range jobs {
+				results, err := fetch(batchClient, chunk)
+
+				mtx.Lock()
+				// Skip any result arriving after the batch was aborted
+				if aborted {
+					mtx.Unlock()
+					continue
+				}
+				stop := err != nil && tcg.failFast
+				if err != nil {
+					failed = append(failed, ChunkError{
+						Ids: chunk,
+						Err: err,
+					})
+				} else {
+					fnErr = fn(results)
+					stop = fnErr != nil
+				}
+				if stop {
+					aborted = true
+					close(abort)
+					cancel()
+				}
+				mtx.Unlock()
+			}
+		}()
+	}
+
+loop:
+	for _, chunk := range chunks {
+		select {
+		case jobs <- chunk:
+		case <-abort:
+			break loop
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Report an expired or cancelled Client context over the errors it caused
+	if tcg.ctx.Err() != nil {
+		return tcg.ctx.Err()
+	}
+	if fnErr != nil {
+		return fnErr
+	}
+	if len(failed) > 0 {
+		return &BatchError{
+			Chunks: failed,
+		}
+	}
+	return nil
+}
+
+// Split ids in chunks of at most size elements, or MaxIdsInRequest if size
+// is not positive, so that each chunk can be passed to a single request
+func Chunk(ids []int, size int) [][]int {
+	if size <= 0 {
+		size = MaxIdsInRequest
+	}
+
+	var out [][]int
+	for len(ids) > size {
+		out = append(out, ids[:size])
+		ids = ids[size:]
+	}
+	if len(ids) > 0 {
+		out = append(out, ids)
+	}
+	return out
+}
+
+// Drop repeated ids, keeping the first occurrence, without modifying the input
+func uniqueIds(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	out := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
+
+// Sort items to follow the order of the ids they were requested with, as
+// chunks may complete in any order, keeping items with unknown ids last
+func sortByIds[T any](items []T, ids []int, key func(T) int) {
+	position := make(map[int]int, len(ids))
+	for i, id := range ids {
+		_, found := position[id]
+		if !found {
+			position[id] = i
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, foundA := position[key(items[i])]
+		b, foundB := position[key(items[j])]
+		if !foundA || !foundB {
+			return foundA && !foundB
+		}
+		return a < b
+	})
+}
@@ -0,0 +1,113 @@
+package tcgplayer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sequentialIds(n int) []int {
+	ids := make([]int, n)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	return ids
+}
+
+func TestGetAllMarketPricesBySKUsChunks(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		ids := writeSKUPrices(w, r)
+		if len(ids) > MaxIdsInRequest {
+			t.Errorf("requested %d ids at once", len(ids))
+		}
+	})
+
+	prices, err := stub.client().GetAllMarketPricesBySKUs(sequentialIds(600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != 600 {
+		t.Errorf("got %d prices, expected 600", len(prices))
+	}
+	if n := stub.requests.Load(); n != 3 {
+		t.Errorf("sent %d requests, expected 3", n)
+	}
+}
+
+func TestBatchFailFast(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+
+	tcg := stub.client(WithFailFast(true))
+	// Shared by the batch requests, which reuse the http client
+	tcg.client.RetryMax = 0
+	_, err := tcg.GetAllMarketPricesBySKUs(sequentialIds(20 * MaxIdsInRequest))
+	if err == nil {
+		t.Fatal("expected the batch to fail")
+	}
+	// Only the chunks already in progress when the first one failed were sent
+	if n := stub.requests.Load(); n > defaultBatchConcurrency {
+		t.Errorf("sent %d requests, expected the batch to stop after the first failure", n)
+	}
+}
+
+func TestBatchDeadline(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// Only the first chunk answers in time
+		if !strings.HasPrefix(r.URL.Path, "/pricing/sku/1,") {
+			select {
+			case <-time.After(5 * time.Second):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		writeSKUPrices(w, r)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	tcg := stub.client(WithContext(ctx))
+
+	start := time.Now()
+	prices, err := tcg.GetAllMarketPricesBySKUs(sequentialIds(3 * MaxIdsInRequest))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline error, got %v", err)
+	}
+	if len(prices) != MaxIdsInRequest {
+		t.Errorf("got %d prices, expected the %d of the first chunk", len(prices), MaxIdsInRequest)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("batch took %s, past its deadline", elapsed)
+	}
+}
+
+func TestBatchInputOrder(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// Complete the first chunk last
+		if strings.HasPrefix(r.URL.Path, "/pricing/sku/600,") {
+			time.Sleep(100 * time.Millisecond)
+		}
+		writeSKUPrices(w, r)
+	})
+
+	ids := sequentialIds(600)
+	sort.Sort(sort.Reverse(sort.IntSlice(ids)))
+
+	prices, err := stub.client().GetAllMarketPricesBySKUs(ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != len(ids) {
+		t.Fatalf("got %d prices, expected %d", len(prices), len(ids))
+	}
+	for i, price := range prices {
+		if price.SkuId != ids[i] {
+			t.Fatalf("price %d is for SKU %d, expected %d", i, price.SkuId, ids[i])
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package tcgplayer
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Duration of the window used by WithDailyRequestBudget
+const budgetWindow = 24 * time.Hour
+
+var ErrBudgetExceeded = errors.New("request budget exceeded")
+
+// Requests of the last 24 hours, grouped by the second they were made in,
+// so that memory does not grow with the request rate
+type requestBudget struct {
+	mtx     sync.Mutex
+	limit   int
+	count   int
+	buckets []budgetBucket
+}
+
+type budgetBucket struct {
+	start time.Time
+	count int
+}
+
+// Account for a new request, failing if the budget was exhausted
+func (b *requestBudget) take() error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	now := time.Now()
+	b.prune(now)
+	if b.limit > 0 && b.count >= b.limit {
+		return ErrBudgetExceeded
+	}
+	b.count++
+
+	last := len(b.buckets) - 1
+	if last >= 0 && now.Sub(b.buckets[last].start) < time.Second {
+		b.buckets[last].count++
+	} else {
+		b.buckets = append(b.buckets, budgetBucket{start: now, count: 1})
+	}
+	return nil
+}
+
+// Forget the requests that left the window
+func (b *requestBudget) prune(now time.Time) {
+	i := 0
+	for i < len(b.buckets) && now.Sub(b.buckets[i].start) >= budgetWindow {
+		b.count -= b.buckets[i].count
+		i++
+	}
+	b.buckets = b.buckets[i:]
+}
+
+type Stats struct {
+	// Number of requests performed in the last 24 hours, including retries
+	Requests int
+	// Number of requests still allowed right now, or -1 if the Client has
+	// no budget set
+	Remaining int
+	// When the oldest request of the last 24 hours leaves the window, freeing
+	// up budget, zero if no request was made in the last 24 hours
+	WindowReset time.Time
+}
+
+// Report how many requests were made and how many are left in the budget
+func (tcg *Client) Stats() Stats {
+	b := tcg.budget
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.prune(time.Now())
+	stats := Stats{
+		Requests:  b.count,
+		Remaining: -1,
+	}
+	if len(b.buckets) > 0 {
+		stats.WindowReset = b.buckets[0].start.Add(budgetWindow)
+	}
+	if b.limit > 0 {
+		stats.Remaining = b.limit - stats.Requests
+	}
+	return stats
+}
@@ -0,0 +1,63 @@
+package tcgplayer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestRequestBudgetSlidingWindow(t *testing.T) {
+	now := time.Now()
+	b := &requestBudget{
+		limit: 3,
+		count: 3,
+		buckets: []budgetBucket{
+			{start: now.Add(-budgetWindow - time.Minute), count: 1},
+			{start: now.Add(-time.Hour), count: 2},
+		},
+	}
+
+	// The oldest request left the window, freeing a single request
+	err := b.take()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.take()
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected the budget to be exceeded, got %v", err)
+	}
+
+	tcg := &Client{budget: b}
+	stats := tcg.Stats()
+	if stats.Requests != 3 || stats.Remaining != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	expected := now.Add(-time.Hour).Add(budgetWindow)
+	if !stats.WindowReset.Equal(expected) {
+		t.Errorf("window resets at %s, expected %s", stats.WindowReset, expected)
+	}
+}
+
+func TestRequestBudgetRateLimited(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		writeResults(w, 0, []Printing{})
+	})
+
+	// With a single token per hour, the second request never leaves the limiter
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	tcg := stub.client(WithDailyRequestBudget(10), WithRateLimit(rate.Every(time.Hour), 1), WithContext(ctx))
+	for i := 0; i < 2; i++ {
+		tcg.ListCategoryPrintings(CategoryMagic)
+	}
+	if n := stub.requests.Load(); n != 1 {
+		t.Fatalf("sent %d requests, expected 1", n)
+	}
+	if n := tcg.Stats().Requests; n != 1 {
+		t.Errorf("budget counted %d requests, expected only the one sent", n)
+	}
+}
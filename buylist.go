@@ -0,0 +1,152 @@
+package tcgplayer
+
+import (
+	"errors"
+	"strings"
+)
+
+// Buylist prices of a product or a SKU. Only the high and market prices are
+// decoded, and either is nil when missing, reading as zero through High and
+// Market.
+type BuylistPrices struct {
+	HighPrice   *float64 `json:"high,omitempty"`
+	MarketPrice *float64 `json:"market,omitempty"`
+}
+
+type SKUBuylistPriceSet struct {
+	SkuId  int           `json:"skuId"`
+	Prices BuylistPrices `json:"prices"`
+}
+
+func (tcg *Client) GetBuylistPricesBySKUs(skuIds []int) ([]SKUBuylistPriceSet, error) {
+	if len(skuIds) > MaxIdsInRequest {
+		return nil, errors.New("too many ids in request")
+	}
+
+	ids := ints2strings(skuIds)
+	link := tcgApiBuylistSkuURL + "/" + strings.Join(ids, ",")
+
+	out, _, err := getResults[SKUBuylistPriceSet](tcg, link)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Retrieve buylist prices for any number of SKUs, splitting the ids in chunks
+// of MaxIdsInRequest, and passing each completed chunk to fn
+func (tcg *Client) GetAllBuylistPricesBySKUsFunc(skuIds []int, fn func([]SKUBuylistPriceSet) error) error {
+	return runBatches(tcg, skuIds, (*Client).GetBuylistPricesBySKUs, fn)
+}
+
+// Retrieve buylist prices for any number of SKUs, in the same order as the
+// input ids.
+// On error, the results of the chunks completed so far are returned as well.
+func (tcg *Client) GetAllBuylistPricesBySKUs(skuIds []int) ([]SKUBuylistPriceSet, error) {
+	var out []SKUBuylistPriceSet
+	err := tcg.GetAllBuylistPricesBySKUsFunc(skuIds, func(prices []SKUBuylistPriceSet) error {
+		out = append(out, prices...)
+		return nil
+	})
+	sortByIds(out, skuIds, func(item SKUBuylistPriceSet) int {
+		return item.SkuId
+	})
+	return out, err
+}
+
+// Buylist prices of a product, overall and for each of its SKUs
+type ProductBuylistPriceSet struct {
+	ProductId int                  `json:"productId"`
+	Prices    BuylistPrices        `json:"prices"`
+	Skus      []SKUBuylistPriceSet `json:"skus"`
+}
+
+func (tcg *Client) GetBuylistPricesByProducts(productIds []int) ([]ProductBuylistPriceSet, error) {
+	if len(productIds) > MaxIdsInRequest {
+		return nil, errors.New("too many ids in request")
+	}
+
+	ids := ints2strings(productIds)
+	link := tcgApiBuylistProductURL + "/" + strings.Join(ids, ",")
+
+	out, _, err := getResults[ProductBuylistPriceSet](tcg, link)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Retrieve buylist prices for any number of products, splitting the ids in
+// chunks of MaxIdsInRequest, and passing each completed chunk to fn
+func (tcg *Client) GetAllBuylistPricesByProductsFunc(productIds []int, fn func([]ProductBuylistPriceSet) error) error {
+	return runBatches(tcg, productIds, (*Client).GetBuylistPricesByProducts, fn)
+}
+
+// Retrieve buylist prices for any number of products, in the same order as
+// the input ids.
+// On error, the results of the chunks completed so far are returned as well.
+func (tcg *Client) GetAllBuylistPricesByProducts(productIds []int) ([]ProductBuylistPriceSet, error) {
+	var out []ProductBuylistPriceSet
+	err := tcg.GetAllBuylistPricesByProductsFunc(productIds, func(prices []ProductBuylistPriceSet) error {
+		out = append(out, prices...)
+		return nil
+	})
+	sortByIds(out, productIds, func(item ProductBuylistPriceSet) int {
+		return item.ProductId
+	})
+	return out, err
+}
+
+// Market and buylist prices of a SKU, either may be nil if not available
+type CombinedSKUPrice struct {
+	SkuId   int            `json:"skuId"`
+	Market  *SKUPriceSet   `json:"market,omitempty"`
+	Buylist *BuylistPrices `json:"buylist,omitempty"`
+}
+
+// Retrieve both market and buylist prices for any number of SKUs, joined by
+// SkuId, in the same order as the input ids. SKUs without any price are
+// skipped.
+func (tcg *Client) GetAllPricesBySKUs(skuIds []int) ([]CombinedSKUPrice, error) {
+	market, err := tcg.GetAllMarketPricesBySKUs(skuIds)
+	if err != nil {
+		return nil, err
+	}
+	buylist, err := tcg.GetAllBuylistPricesBySKUs(skuIds)
+	if err != nil {
+		return nil, err
+	}
+
+	combined := map[int]*CombinedSKUPrice{}
+	for i := range market {
+		combined[market[i].SkuId] = &CombinedSKUPrice{
+			SkuId:  market[i].SkuId,
+			Market: &market[i],
+		}
+	}
+	for i := range buylist {
+		price, found := combined[buylist[i].SkuId]
+		if !found {
+			price = &CombinedSKUPrice{
+				SkuId: buylist[i].SkuId,
+			}
+			combined[buylist[i].SkuId] = price
+		}
+		price.Buylist = &buylist[i].Prices
+	}
+
+	out := make([]CombinedSKUPrice, 0, len(combined))
+	for _, skuId := range skuIds {
+		price, found := combined[skuId]
+		if !found {
+			continue
+		}
+		out = append(out, *price)
+		// Skip any duplicate id
+		delete(combined, skuId)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,31 @@
+package tcgplayer
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBuylistPricesMissing(t *testing.T) {
+	var prices []SKUBuylistPriceSet
+	err := json.Unmarshal([]byte(`[
+		{"skuId": 1, "prices": {"high": 2.5, "market": 1.25}},
+		{"skuId": 2, "prices": {"high": null, "market": 0}},
+		{"skuId": 3, "prices": {}}
+	]`), &prices)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if prices[0].Prices.High() != 2.5 || prices[0].Prices.Market() != 1.25 {
+		t.Errorf("unexpected prices %+v", prices[0].Prices)
+	}
+	if prices[1].Prices.HighPrice != nil || prices[1].Prices.MarketPrice == nil {
+		t.Errorf("a zero price should be kept apart from a missing one: %+v", prices[1].Prices)
+	}
+	if prices[2].Prices.HighPrice != nil || prices[2].Prices.MarketPrice != nil {
+		t.Errorf("missing prices should be nil: %+v", prices[2].Prices)
+	}
+	if prices[2].Prices.High() != 0 || prices[2].Prices.Market() != 0 {
+		t.Errorf("missing prices should read as zero: %+v", prices[2].Prices)
+	}
+}
@@ -0,0 +1,264 @@
+package tcgplayer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Per-category catalog metadata, which rarely changes and is safe to keep
+// around for the lifetime of a Client
+type metadataCache struct {
+	mtx          sync.RWMutex
+	conditions   map[int][]Condition
+	printings    map[int][]Printing
+	productTypes map[int][]string
+	groups       map[int][]Group
+
+	extendedFields map[int][]ExtendedField
+	metadata       map[int]*CategoryMetadata
+}
+
+func newMetadataCache() *metadataCache {
+	return &metadataCache{
+		conditions:   map[int][]Condition{},
+		printings:    map[int][]Printing{},
+		productTypes: map[int][]string{},
+		groups:       map[int][]Group{},
+
+		extendedFields: map[int][]ExtendedField{},
+		metadata:       map[int]*CategoryMetadata{},
+	}
+}
+
+// Same as ListCategoryConditions, but only request them once per category
+func (tcg *Client) categoryConditions(category int) ([]Condition, error) {
+	tcg.cache.mtx.RLock()
+	conditions, found := tcg.cache.conditions[category]
+	tcg.cache.mtx.RUnlock()
+	if found {
+		return conditions, nil
+	}
+
+	conditions, err := tcg.ListCategoryConditions(category)
+	if err != nil {
+		return nil, err
+	}
+
+	tcg.cache.mtx.Lock()
+	tcg.cache.conditions[category] = conditions
+	tcg.cache.mtx.Unlock()
+
+	return conditions, nil
+}
+
+// Same as ListCategoryPrintings, but only request them once per category
+func (tcg *Client) categoryPrintings(category int) ([]Printing, error) {
+	tcg.cache.mtx.RLock()
+	printings, found := tcg.cache.printings[category]
+	tcg.cache.mtx.RUnlock()
+	if found {
+		return printings, nil
+	}
+
+	printings, err := tcg.ListCategoryPrintings(category)
+	if err != nil {
+		return nil, err
+	}
+
+	tcg.cache.mtx.Lock()
+	tcg.cache.printings[category] = printings
+	tcg.cache.mtx.Unlock()
+
+	return printings, nil
+}
+
+// Retrieve the printings of several categories, indexed by category id,
+// requesting them concurrently with the given number of workers, all sharing
+// the Client rate limiter. Printings are cached for the lifetime of the
+// Client, so only categories never seen before perform any request.
+// On error, the printings retrieved so far are returned as well.
+func (tcg *Client) ListCategoriesPrintings(categoryIds []int, workers int) (map[int][]Printing, error) {
+	out := make(map[int][]Printing, len(categoryIds))
+	var mtx sync.Mutex
+	err := forEachCategory(categoryIds, workers, func(category int) error {
+		printings, err := tcg.categoryPrintings(category)
+		if err != nil {
+			return err
+		}
+		mtx.Lock()
+		out[category] = printings
+		mtx.Unlock()
+		return nil
+	})
+	return out, err
+}
+
+// Sort SKUs from the best to the worst condition (Near Mint first), following
+// the DisplayOrder of the category conditions, which are retrieved only once
+func (tcg *Client) SortSKUsByCondition(category int, skus []SKU) error {
+	conditions, err := tcg.categoryConditions(category)
+	if err != nil {
+		return err
+	}
+
+	order := map[int]int{}
+	for _, condition := range conditions {
+		order[condition.ConditionId] = condition.DisplayOrder
+	}
+
+	sort.SliceStable(skus, func(i, j int) bool {
+		a, foundA := order[skus[i].ConditionId]
+		b, foundB := order[skus[j].ConditionId]
+		// Unknown conditions go last
+		if !foundA || !foundB {
+			return foundA && !foundB
+		}
+		return a < b
+	})
+
+	return nil
+}
+
+// Retrieve the product types of a category, as listed by the "ProductType"
+// filter of its search manifest, so that types not listed in AllProductTypes
+// are found as well. If the manifest has no such filter, an error wrapping
+// ErrNotFound is returned. Results are cached for the lifetime of the Client.
+func (tcg *Client) DiscoverProductTypes(category int) ([]string, error) {
+	tcg.cache.mtx.RLock()
+	productTypes, found := tcg.cache.productTypes[category]
+	tcg.cache.mtx.RUnlock()
+	if found {
+		return productTypes, nil
+	}
+
+	manifest, err := tcg.GetSearchManifest(category)
+	if err != nil {
+		return nil, err
+	}
+
+	found = false
+	for _, filter := range manifest.Filters {
+		if filter.Name != "ProductType" {
+			continue
+		}
+		found = true
+		for _, item := range filter.Items {
+			productTypes = append(productTypes, item.Value)
+		}
+		break
+	}
+	if !found {
+		return nil, fmt.Errorf("product types of category %d: %w", category, ErrNotFound)
+	}
+
+	tcg.cache.mtx.Lock()
+	tcg.cache.productTypes[category] = productTypes
+	tcg.cache.mtx.Unlock()
+
+	return productTypes, nil
+}
+
+// Same as GetAllCategoryGroups, but only request them once per category
+func (tcg *Client) categoryGroups(category int) ([]Group, error) {
+	tcg.cache.mtx.RLock()
+	groups, found := tcg.cache.groups[category]
+	tcg.cache.mtx.RUnlock()
+	if found {
+		return groups, nil
+	}
+
+	groups, err := tcg.GetAllCategoryGroups(category)
+	if err != nil {
+		return nil, err
+	}
+
+	tcg.cache.mtx.Lock()
+	tcg.cache.groups[category] = groups
+	tcg.cache.mtx.Unlock()
+
+	return groups, nil
+}
+
+// Find all the groups of a category with the given abbreviation, ignoring
+// case. The groups list is retrieved only once per category.
+func (tcg *Client) FindGroupsByAbbreviation(category int, abbrev string) ([]Group, error) {
+	groups, err := tcg.categoryGroups(category)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Group
+	for _, group := range groups {
+		if strings.EqualFold(group.Abbreviation, abbrev) {
+			out = append(out, group)
+		}
+	}
+	return out, nil
+}
+
+// Find the group of a category with the given abbreviation, ignoring case.
+// If the abbreviation is ambiguous, the first match is returned, use
+// FindGroupsByAbbreviation to retrieve all of them.
+func (tcg *Client) FindGroupByAbbreviation(category int, abbrev string) (Group, bool, error) {
+	groups, err := tcg.FindGroupsByAbbreviation(category, abbrev)
+	if err != nil || len(groups) == 0 {
+		return Group{}, false, err
+	}
+	return groups[0], true, nil
+}
+
+// How many pages of products are sampled by DiscoverExtendedFields
+const extendedFieldsSamplePages = 3
+
+type ExtendedField struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// Find which extended fields are used by the products of a category, by
+// sampling the first pages of products. Fields used only by products outside
+// of the sample may be missing. Results are sorted by name, and cached for the
+// lifetime of the Client.
+func (tcg *Client) DiscoverExtendedFields(category int) ([]ExtendedField, error) {
+	tcg.cache.mtx.RLock()
+	fields, found := tcg.cache.extendedFields[category]
+	tcg.cache.mtx.RUnlock()
+	if found {
+		return fields, nil
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i < extendedFieldsSamplePages; i++ {
+		products, err := tcg.ListAllProducts(category, nil, false, i*MaxItemsInResponse)
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range products {
+			for _, data := range product.ExtendedData {
+				if seen[data.Name] {
+					continue
+				}
+				seen[data.Name] = true
+				fields = append(fields, ExtendedField{
+					Name:        data.Name,
+					DisplayName: data.DisplayName,
+				})
+			}
+		}
+		if len(products) < MaxItemsInResponse {
+			break
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Name < fields[j].Name
+	})
+
+	tcg.cache.mtx.Lock()
+	tcg.cache.extendedFields[category] = fields
+	tcg.cache.mtx.Unlock()
+
+	return fields, nil
+}
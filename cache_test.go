@@ -0,0 +1,65 @@
+package tcgplayer
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDiscoverProductTypes(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/catalog/categories/1/search/manifest":
+			w.Write([]byte(`{
+				"totalItems": 1,
+				"success": true,
+				"errors": [],
+				"results": [{
+					"sorting": [{"text": "Relevance", "value": "Relevance"}],
+					"filters": [
+						{"name": "ProductName", "displayName": "Product Name", "inputType": "Text", "items": []},
+						{"name": "ProductType", "displayName": "Product Type", "inputType": "SingleSelect", "items": [
+							{"text": "Cards", "value": "Cards"},
+							{"text": "Sealed Products", "value": "Sealed Products"},
+							{"text": "Playmats", "value": "Playmats"}
+						]}
+					]
+				}]
+			}`))
+		case "/catalog/categories/2/search/manifest":
+			w.Write([]byte(`{
+				"totalItems": 1,
+				"success": true,
+				"errors": [],
+				"results": [{
+					"sorting": [],
+					"filters": [{"name": "ProductName", "displayName": "Product Name", "inputType": "Text", "items": []}]
+				}]
+			}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	tcg := stub.client()
+
+	for i := 0; i < 2; i++ {
+		productTypes, err := tcg.DiscoverProductTypes(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []string{"Cards", "Sealed Products", "Playmats"}
+		if !reflect.DeepEqual(productTypes, expected) {
+			t.Fatalf("got %v, expected %v", productTypes, expected)
+		}
+	}
+	if n := stub.requests.Load(); n != 1 {
+		t.Errorf("manifest requested %d times, expected once", n)
+	}
+
+	_, err := tcg.DiscoverProductTypes(2)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a not found error without a ProductType filter, got %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package tcgplayer
+
+import (
+	"sort"
+	"sync"
+)
+
+// Names of the known categories. The placeholders of the Category const
+// block (ids 5, 21, 83 and 84) are intentionally missing, as they do not map
+// to any usable category.
+var categoryNames = map[int]string{
+	CategoryMagic:                         "Magic: The Gathering",
+	CategoryYuGiOh:                        "YuGiOh",
+	CategoryPokemon:                       "Pokemon",
+	CategoryAxisAllies:                    "Axis & Allies",
+	CategoryDDMiniatures:                  "D & D Miniatures",
+	CategoryEpic:                          "Epic",
+	CategoryHeroclix:                      "Heroclix",
+	CategoryMonsterpocalypse:              "Monsterpocalypse",
+	CategoryRedakai:                       "Redakai",
+	CategoryStarWarsMiniatures:            "Star Wars Miniatures",
+	CategoryWorldOfWarcraftMiniatures:     "World of Warcraft Miniatures",
+	CategoryWoW:                           "WoW",
+	CategorySupplies:                      "Supplies",
+	CategoryOrganizersStores:              "Organizers & Stores",
+	CategoryChronoClashSystem:             "Chrono Clash System",
+	CategoryForceOfWill:                   "Force of Will",
+	CategoryDiceMasters:                   "Dice Masters",
+	CategoryFutureCardBuddyFight:          "Future Card BuddyFight",
+	CategoryWeissSchwarz:                  "Weiss Schwarz",
+	CategoryTCGplayer:                     "TCGplayer",
+	CategoryDragonBallZ:                   "Dragon Ball Z TCG",
+	CategoryFinalFantasy:                  "Final Fantasy TCG",
+	CategoryUniVersus:                     "UniVersus",
+	CategoryStarWarsDestiny:               "Star Wars: Destiny",
+	CategoryDragonBallSuper:               "Dragon Ball Super CCG",
+	CategoryDragoborne:                    "Dragoborne",
+	CategoryFunko:                         "Funko",
+	CategoryMetaX:                         "MetaX TCG",
+	CategoryCardSleeves:                   "Card Sleeves",
+	CategoryDeckBoxes:                     "Deck Boxes",
+	CategoryCardStorageTins:               "Card Storage Tins",
+	CategoryLifeCounters:                  "Life Counters",
+	CategoryPlaymats:                      "Playmats",
+	CategoryZombieWorldOrder:              "Zombie World Order TCG",
+	CategoryTheCasterChronicles:           "The Caster Chronicles",
+	CategoryMyLittlePony:                  "My Little Pony CCG",
+	CategoryWarhammerBooks:                "Warhammer Books",
+	CategoryWarhammerBigBoxGames:          "Warhammer Big Box Games",
+	CategoryWarhammerBoxSets:              "Warhammer Box Sets",
+	CategoryWarhammerClampacks:            "Warhammer Clampacks",
+	CategoryCitadelPaints:                 "Citadel Paints",
+	CategoryCitadelTools:                  "Citadel Tools",
+	CategoryWarhammerGameAccessories:      "Warhammer Game Accessories",
+	CategoryBooks:                         "Books",
+	CategoryExodus:                        "Exodus TCG",
+	CategoryLightseekers:                  "Lightseekers TCG",
+	CategoryProtectivePages:               "Protective Pages",
+	CategoryStorageAlbums:                 "Storage Albums",
+	CategoryCollectibleStorage:            "Collectible Storage",
+	CategorySupplyBundles:                 "Supply Bundles",
+	CategoryMunchkin:                      "Munchkin CCG",
+	CategoryWarhammerAgeOfSigmarChampions: "Warhammer Age of Sigmar Champions TCG",
+	CategoryArchitect:                     "Architect TCG",
+	CategoryBulkLots:                      "Bulk Lots",
+	CategoryTransformers:                  "Transformers TCG",
+	CategoryBakugan:                       "Bakugan TCG",
+	CategoryKeyForge:                      "KeyForge",
+	CategoryCardfightVanguard:             "Cardfight Vanguard",
+	CategoryArgentSaga:                    "Argent Saga TCG",
+	CategoryFleshAndBlood:                 "Flesh and Blood TCG",
+	CategoryDigimon:                       "Digimon Card Game",
+	CategoryAlternateSouls:                "Alternate Souls",
+	CategoryGateRuler:                     "Gate Ruler",
+	CategoryMetaZoo:                       "MetaZoo",
+	CategoryWIXOSS:                        "WIXOSS",
+	CategoryOnePiece:                      "One Piece Card Game",
+	CategoryMarvelComics:                  "Marvel Comics",
+	CategoryDCComics:                      "DC Comics",
+	CategoryLorcana:                       "Lorcana TCG",
+	CategoryBattleSpiritsSaga:             "Battle Spirits Saga",
+	CategoryShadowverseEvolve:             "Shadowverse: Evolve",
+	CategoryGrandArchive:                  "Grand Archive",
+	CategoryAkora:                         "Akora",
+	CategoryKryptik:                       "Kryptik TCG",
+	CategorySorceryContestedRealm:         "Sorcery: Contested Realm",
+	CategoryAlphaClash:                    "Alpha Clash",
+	CategoryStarWarsUnlimited:             "Star Wars: Unlimited",
+	CategoryDragonBallSuperFusionWorld:    "Dragon Ball Super: Fusion World",
+	CategoryUnionArena:                    "Union Arena",
+	CategoryTCGplayerSupplies:             "TCGplayer Supplies",
+}
+
+// List of all the known category ids, in ascending order, placeholders excluded
+var AllCategories = sortedCategories()
+
+func sortedCategories() []int {
+	out := make([]int, 0, len(categoryNames))
+	for category := range categoryNames {
+		out = append(out, category)
+	}
+	sort.Ints(out)
+	return out
+}
+
+// Return the name of a known category, or false for unknown ids and for the
+// placeholders of the Category const block
+func CategoryName(category int) (string, bool) {
+	name, found := categoryNames[category]
+	return name, found
+}
+
+// Retrieve the number of groups of each of the given categories, indexed by
+// category id, requesting them concurrently with the given number of workers,
+// all sharing the Client rate limiter.
+// On error, the counts retrieved so far are returned as well.
+func (tcg *Client) ListCategoriesWithGroupCounts(categoryIds []int, workers int) (map[int]int, error) {
+	out := make(map[int]int, len(categoryIds))
+	var mtx sync.Mutex
+	err := forEachCategory(categoryIds, workers, func(category int) error {
+		total, err := tcg.TotalGroups(category)
+		if err != nil {
+			return err
+		}
+		mtx.Lock()
+		out[category] = total
+		mtx.Unlock()
+		return nil
+	})
+	return out, err
+}
+
+// Call fn for each category from the given number of workers, at least one,
+// and return the first error encountered, after all the calls are completed
+func forEachCategory(categoryIds []int, workers int, fn func(category int) error) error {
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(categoryIds) {
+		workers = len(categoryIds)
+	}
+
+	jobs := make(chan int)
+	var once sync.Once
+	var firstErr error
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for category := range jobs {
+				err := fn(category)
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+					})
+				}
+			}
+		}()
+	}
+
+	for _, category := range categoryIds {
+		jobs <- category
+	}
+	close(jobs)
+	wg.Wait()
+
+	return firstErr
+}
@@ -0,0 +1,77 @@
+package tcgplayer
+
+import (
+	"os"
+	"testing"
+)
+
+// Ids skipped by the Category const block
+var categoryPlaceholders = []int{5, 21, 83, 84}
+
+func TestCategoryNamePlaceholders(t *testing.T) {
+	for _, category := range []int{CategoryMagic, CategoryAxisAllies, CategoryDDMiniatures, CategoryWeissSchwarz, CategoryTCGplayer, CategoryTCGplayerSupplies} {
+		name, found := CategoryName(category)
+		if !found || name == "" {
+			t.Errorf("category %d should be named", category)
+		}
+	}
+	for _, category := range append([]int{0, -1, 1000}, categoryPlaceholders...) {
+		name, found := CategoryName(category)
+		if found || name != "" {
+			t.Errorf("category %d should not be named, got %q", category, name)
+		}
+	}
+}
+
+// Check the Category constants against the live API, only when keys are set
+func TestCategoriesLive(t *testing.T) {
+	publicKey := os.Getenv("TCGPLAYER_PUBLIC_KEY")
+	privateKey := os.Getenv("TCGPLAYER_PRIVATE_KEY")
+	if publicKey == "" || privateKey == "" {
+		t.Skip("TCGPLAYER_PUBLIC_KEY and TCGPLAYER_PRIVATE_KEY are not set")
+	}
+
+	ids := append(append([]int{}, AllCategories...), categoryPlaceholders...)
+	categories, err := NewClient(publicKey, privateKey).GetCategoriesDetails(ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	live := map[int]Category{}
+	for _, category := range categories {
+		live[category.CategoryID] = category
+	}
+
+	for _, category := range AllCategories {
+		if _, found := live[category]; !found {
+			t.Errorf("category %d is not listed by the API", category)
+		}
+	}
+	for _, category := range categoryPlaceholders {
+		if details, found := live[category]; found {
+			t.Logf("placeholder %d is listed by the API as %q", category, details.Name)
+		}
+	}
+}
+
+func TestAllCategoriesNamed(t *testing.T) {
+	placeholders := map[int]bool{}
+	for _, category := range categoryPlaceholders {
+		placeholders[category] = true
+	}
+
+	for i, category := range AllCategories {
+		name, found := CategoryName(category)
+		if !found || name == "" {
+			t.Errorf("category %d has no name", category)
+		}
+		if placeholders[category] {
+			t.Errorf("placeholder %d is listed in AllCategories", category)
+		}
+		if i > 0 && AllCategories[i-1] >= category {
+			t.Errorf("AllCategories is not sorted at %d", category)
+		}
+	}
+	if AllCategories[len(AllCategories)-1] != CategoryTCGplayerSupplies {
+		t.Errorf("last category is %d, expected %d", AllCategories[len(AllCategories)-1], CategoryTCGplayerSupplies)
+	}
+}
@@ -0,0 +1,59 @@
+package tcgplayer
+
+import (
+	"time"
+)
+
+// Retrieve the groups of a category modified after the given time.
+// The modifiedOn field of a group is only a coarse signal: it changes when
+// the group itself or some of its products are edited, but it is not
+// guaranteed to follow every product edit. It is meant to narrow down which
+// groups need to have their products paged during an incremental sync.
+func (tcg *Client) GroupsLikelyChangedSince(category int, since time.Time) ([]Group, error) {
+	groups, err := tcg.GetAllCategoryGroups(category)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Group
+	for _, group := range groups {
+		modified, err := group.ModifiedTime()
+		// Keep groups with unknown modification time, just in case
+		if err != nil || modified.After(since) {
+			out = append(out, group)
+		}
+	}
+
+	return out, nil
+}
+
+// Retrieve the products of a category modified in the [from, to) window,
+// that is from inclusive and to exclusive. Filtering happens client-side, so
+// every page of products of the category is requested, costing as much as a
+// full dump of the category. Products without a valid modification time are
+// skipped.
+func (tcg *Client) ListProductsModifiedBetween(category int, from, to time.Time, productTypes []string) ([]Product, error) {
+	total, err := tcg.TotalProducts(category, productTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Product
+	for offset := 0; offset < total; offset += MaxItemsInResponse {
+		products, err := tcg.ListAllProducts(category, productTypes, false, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range products {
+			modified, err := product.ModifiedTime()
+			if err != nil {
+				continue
+			}
+			if !modified.Before(from) && modified.Before(to) {
+				out = append(out, product)
+			}
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,39 @@
+package tcgplayer
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListProductsModifiedBetween(t *testing.T) {
+	products := []Product{
+		{ProductId: 1, ModifiedOn: "2024-01-01T23:59:59.999"},
+		{ProductId: 2, ModifiedOn: "2024-01-02T00:00:00"},
+		{ProductId: 3, ModifiedOn: "2024-01-02T12:00:00.5"},
+		{ProductId: 4, ModifiedOn: "2024-01-02T23:59:59.999"},
+		{ProductId: 5, ModifiedOn: "2024-01-03T00:00:00"},
+		{ProductId: 6, ModifiedOn: ""},
+	}
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		writeResults(w, len(products), products)
+	})
+
+	from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	found, err := stub.client().ListProductsModifiedBetween(CategoryMagic, from, to, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []int
+	for _, product := range found {
+		ids = append(ids, product.ProductId)
+	}
+	// from is included, to is excluded
+	expected := []int{2, 3, 4}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("got products %v, expected %v", ids, expected)
+	}
+}
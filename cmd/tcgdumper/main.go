@@ -1,22 +1,46 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 
 	"github.com/mtgban/go-tcgplayer"
 )
 
+type categoryDump struct {
+	Category tcgplayer.Category `json:"category"`
+	Groups   []tcgplayer.Group  `json:"groups"`
+	Products []product          `json:"products"`
+}
+
+type product struct {
+	tcgplayer.Product
+
+	// Only present when prices are requested, keyed by subtype (Normal, Foil...)
+	Prices map[string]tcgplayer.ProductPriceSet `json:"prices,omitempty"`
+}
+
 func run() int {
-	categoryOpt := flag.Int("category", 0, "category id to dump")
+	categoryOpt := flag.String("category", "", "category id to dump, or a comma-separated list of ids")
 	tcgPublicKeyOpt := flag.String("pub", "", "TCGplayer public key")
 	tcgPrivateKeyOpt := flag.String("pri", "", "TCGplayer private key")
 	threadOpt := flag.Int("thread", 8, "How many threads to spawn")
+	outOpt := flag.String("out", "", "Write output to this file instead of stdout, or to <file>.partial if the dump is incomplete")
+	pricesOpt := flag.Bool("prices", false, "Include market prices of each product")
+	selfCheckOpt := flag.Bool("selfcheck", false, "Verify keys and API access by requesting one item of each kind, then exit")
 	flag.Parse()
 
 	pubEnv := os.Getenv("TCGPLAYER_PUBLIC_KEY")
@@ -32,66 +56,136 @@ func run() int {
 		log.Fatalln("Missing TCGplayer keys")
 	}
 
-	if *categoryOpt == 0 {
+	var categoryIds []int
+	for _, field := range strings.Split(*categoryOpt, ",") {
+		if strings.TrimSpace(field) == "" {
+			continue
+		}
+		categoryId, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			log.Fatalln("Invalid category id", field)
+		}
+		categoryIds = append(categoryIds, categoryId)
+	}
+
+	if *selfCheckOpt {
+		categoryId := tcgplayer.CategoryMagic
+		if len(categoryIds) > 0 {
+			categoryId = categoryIds[0]
+		}
+		tcgClient := tcgplayer.NewClient(*tcgPublicKeyOpt, *tcgPrivateKeyOpt)
+		return selfCheck(tcgClient, categoryId)
+	}
+
+	if len(categoryIds) == 0 {
 		log.Fatalln("Missing category id")
 	}
 
-	tcgClient := tcgplayer.NewClient(*tcgPublicKeyOpt, *tcgPrivateKeyOpt)
+	// Stop fetching on Ctrl-C, but still output what was retrieved so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	tcgClient := tcgplayer.NewClient(*tcgPublicKeyOpt, *tcgPrivateKeyOpt, tcgplayer.WithContext(ctx))
 
-	categories, err := tcgClient.GetCategoriesDetails([]int{*categoryOpt})
+	categories, err := tcgClient.GetCategoriesDetails(categoryIds)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return 1
 	}
 	fmt.Fprintln(os.Stderr, "Retrieved category details")
 
-	totalgroups, err := tcgClient.TotalGroups(*categoryOpt)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		return 1
+	dumps := map[int]*categoryDump{}
+	for _, category := range categories {
+		dumps[category.CategoryID] = &categoryDump{
+			Category: category,
+		}
+	}
+
+	// Groups and products pages of all categories are fetched by the same workers
+	type page struct {
+		category int
+		groups   bool
+		offset   int
+	}
+	type result struct {
+		category int
+		groups   []tcgplayer.Group
+		products []tcgplayer.Product
 	}
-	var groups []tcgplayer.Group
-	for i := 0; i < totalgroups; i += tcgplayer.MaxItemsInResponse {
-		out, err := tcgClient.ListAllCategoryGroups(*categoryOpt, i)
+
+	// The first page of each kind also reports how many pages are left
+	var all []page
+	for _, categoryId := range categoryIds {
+		groups, totalGroups, err := tcgClient.ListAllCategoryGroupsPage(categoryId, 0)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			return 1
 		}
-		groups = append(groups, out...)
-	}
-	fmt.Fprintln(os.Stderr, "Found", len(groups), "groups")
+		fmt.Fprintln(os.Stderr, "Found", totalGroups, "groups in category", categoryId)
 
-	totalProducts, err := tcgClient.TotalProducts(*categoryOpt, tcgplayer.AllProductTypes)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		return 1
+		products, totalProducts, err := tcgClient.ListAllProductsPage(categoryId, tcgplayer.AllProductTypes, true, 0)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Fprintln(os.Stderr, "Found", totalProducts, "products in category", categoryId)
+
+		dump, found := dumps[categoryId]
+		if found {
+			dump.Groups = append(dump.Groups, groups...)
+			for _, item := range products {
+				dump.Products = append(dump.Products, product{Product: item})
+			}
+		}
+
+		for i := tcgplayer.MaxItemsInResponse; i < totalGroups; i += tcgplayer.MaxItemsInResponse {
+			all = append(all, page{category: categoryId, groups: true, offset: i})
+		}
+		for i := tcgplayer.MaxItemsInResponse; i < totalProducts; i += tcgplayer.MaxItemsInResponse {
+			all = append(all, page{category: categoryId, offset: i})
+		}
 	}
-	fmt.Fprintln(os.Stderr, "Found", totalProducts, "products")
 
-	pages := make(chan int)
-	channel := make(chan tcgplayer.Product)
+	pages := make(chan page)
+	channel := make(chan result)
 	var wg sync.WaitGroup
+	// Set when a page could not be retrieved for reasons other than Ctrl-C
+	var failed atomic.Bool
 
 	for i := 0; i < *threadOpt; i++ {
 		wg.Add(1)
 		go func() {
 			for page := range pages {
-				products, err := tcgClient.ListAllProducts(*categoryOpt, tcgplayer.AllProductTypes, true, page)
+				res := result{
+					category: page.category,
+				}
+				var err error
+				if page.groups {
+					res.groups, err = tcgClient.ListAllCategoryGroups(page.category, page.offset)
+				} else {
+					res.products, err = tcgClient.ListAllProducts(page.category, tcgplayer.AllProductTypes, true, page.offset)
+				}
 				if err != nil {
-					fmt.Fprintln(os.Stderr, err)
+					if ctx.Err() == nil {
+						fmt.Fprintln(os.Stderr, err)
+						failed.Store(true)
+					}
 					continue
 				}
-				for _, product := range products {
-					channel <- product
-				}
+				channel <- res
 			}
 			wg.Done()
 		}()
 	}
 
 	go func() {
-		for i := 0; i < totalProducts; i += tcgplayer.MaxItemsInResponse {
-			pages <- i
+	loop:
+		for _, page := range all {
+			select {
+			case pages <- page:
+			case <-ctx.Done():
+				break loop
+			}
 		}
 		close(pages)
 
@@ -99,36 +193,193 @@ func run() int {
 		close(channel)
 	}()
 
-	var products []tcgplayer.Product
 	for result := range channel {
-		products = append(products, result)
+		dump, found := dumps[result.category]
+		if !found {
+			continue
+		}
+		dump.Groups = append(dump.Groups, result.groups...)
+		for _, item := range result.products {
+			dump.Products = append(dump.Products, product{Product: item})
+		}
 	}
 
-	sort.Slice(products, func(i, j int) bool {
-		return products[i].ProductId < products[j].ProductId
-	})
+	if *pricesOpt && ctx.Err() == nil && !failed.Load() {
+		err := addPrices(ctx, tcgClient, dumps, *threadOpt)
+		if err != nil && ctx.Err() == nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if err == nil {
+			fmt.Fprintln(os.Stderr, "Retrieved prices")
+		}
+	}
 
-	var output struct {
-		Category tcgplayer.Category  `json:"category"`
-		Groups   []tcgplayer.Group   `json:"groups"`
-		Products []tcgplayer.Product `json:"products"`
+	interrupted := ctx.Err() != nil
+	if interrupted {
+		fmt.Fprintln(os.Stderr, "Interrupted, dumping partial results")
+	} else if failed.Load() {
+		fmt.Fprintln(os.Stderr, "Some pages could not be retrieved, dumping partial results")
 	}
-	output.Category = categories[0]
-	output.Products = products
-	output.Groups = groups
+	incomplete := interrupted || failed.Load()
 
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	err = enc.Encode(output)
+	for _, dump := range dumps {
+		sort.Slice(dump.Groups, func(i, j int) bool {
+			return dump.Groups[i].GroupID < dump.Groups[j].GroupID
+		})
+		sort.Slice(dump.Products, func(i, j int) bool {
+			return dump.Products[i].ProductId < dump.Products[j].ProductId
+		})
+	}
+
+	// Keep the original output format when dumping a single category,
+	// otherwise key each category dump by its id
+	var output interface{} = dumps
+	if len(categoryIds) == 1 {
+		dump, found := dumps[categoryIds[0]]
+		if !found {
+			fmt.Fprintln(os.Stderr, "Category", categoryIds[0], "not found")
+			return 1
+		}
+		output = dump
+	}
+
+	// Never replace a previous complete dump with a partial one
+	fileName := *outOpt
+	if incomplete && fileName != "" {
+		fileName += ".partial"
+		fmt.Fprintln(os.Stderr, "Writing partial results to", fileName)
+	}
+
+	err = writeOutput(fileName, output)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return 1
 	}
-	fmt.Fprintln(os.Stderr, "Dumped", len(products), "products and", len(groups), "groups")
+	for _, categoryId := range categoryIds {
+		dump, found := dumps[categoryId]
+		if !found {
+			continue
+		}
+		fmt.Fprintln(os.Stderr, "Dumped", len(dump.Products), "products and", len(dump.Groups), "groups of category", categoryId)
+	}
 
+	if incomplete {
+		return 1
+	}
 	return 0
 }
 
+// Retrieve market prices of all the products of the dumps, with the same
+// number of workers used for the pages, and attach them to their product,
+// grouped by subtype
+func addPrices(ctx context.Context, tcgClient *tcgplayer.Client, dumps map[int]*categoryDump, threads int) error {
+	var productIds []int
+	for _, dump := range dumps {
+		for _, item := range dump.Products {
+			productIds = append(productIds, item.ProductId)
+		}
+	}
+
+	type result struct {
+		prices []tcgplayer.ProductPriceSet
+		err    error
+	}
+
+	chunks := make(chan []int)
+	channel := make(chan result)
+	var wg sync.WaitGroup
+
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func() {
+			for chunk := range chunks {
+				prices, err := tcgClient.GetMarketPricesByProducts(chunk)
+				channel <- result{prices: prices, err: err}
+			}
+			wg.Done()
+		}()
+	}
+
+	go func() {
+	loop:
+		for _, chunk := range tcgplayer.Chunk(productIds, tcgplayer.MaxIdsInRequest) {
+			select {
+			case chunks <- chunk:
+			case <-ctx.Done():
+				break loop
+			}
+		}
+		close(chunks)
+
+		wg.Wait()
+		close(channel)
+	}()
+
+	// Keep draining the results to let all the workers finish
+	var err error
+	var prices []tcgplayer.ProductPriceSet
+	for result := range channel {
+		if result.err != nil {
+			if err == nil {
+				err = result.err
+			}
+			continue
+		}
+		prices = append(prices, result.prices...)
+	}
+	if err != nil {
+		return err
+	}
+
+	index := tcgplayer.NormalizePrices(prices)
+	for _, dump := range dumps {
+		for i := range dump.Products {
+			dump.Products[i].Prices = index[dump.Products[i].ProductId]
+		}
+	}
+
+	return nil
+}
+
+// Encode data to stdout, or to the given file, through a temporary file that
+// is renamed only once the output is complete, and removed on failure
+func writeOutput(fileName string, data interface{}) error {
+	if fileName == "" {
+		return encode(os.Stdout, data)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(fileName), filepath.Base(fileName)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	err = encode(tmp, data)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	// CreateTemp only grants access to the owner
+	err = tmp.Chmod(0644)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), fileName)
+}
+
+func encode(w io.Writer, data interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(data)
+}
+
 func main() {
 	os.Exit(run())
 }
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mtgban/go-tcgplayer"
+)
+
+// Exercise the main API endpoints with a single item each, reporting the
+// outcome of every step, and returning non-zero if any of them failed
+func selfCheck(tcgClient *tcgplayer.Client, categoryId int) int {
+	var productId int
+
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{"authentication and category", func() error {
+			_, err := tcgClient.GetCategory(categoryId)
+			return err
+		}},
+		{"group", func() error {
+			groups, err := tcgClient.ListAllCategoryGroups(categoryId, 0)
+			if err == nil && len(groups) == 0 {
+				err = errors.New("no group returned")
+			}
+			return err
+		}},
+		{"product", func() error {
+			products, err := tcgClient.ListAllProducts(categoryId, nil, false, 0)
+			if err == nil && len(products) == 0 {
+				err = errors.New("no product returned")
+			}
+			if err != nil {
+				return err
+			}
+			productId = products[0].ProductId
+			return nil
+		}},
+		{"price", func() error {
+			if productId == 0 {
+				return errors.New("no product to price")
+			}
+			_, err := tcgClient.GetMarketPricesByProducts([]int{productId})
+			return err
+		}},
+	}
+
+	failed := false
+	for _, step := range steps {
+		err := step.run()
+		if err != nil {
+			failed = true
+			fmt.Fprintln(os.Stderr, "FAIL", step.name+":", err)
+			continue
+		}
+		fmt.Fprintln(os.Stderr, "OK  ", step.name)
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
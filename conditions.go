@@ -0,0 +1,41 @@
+package tcgplayer
+
+import (
+	"strings"
+)
+
+// Standard condition ids, as used by Magic, other categories may differ
+const (
+	ConditionNearMint = iota + 1
+	ConditionLightlyPlayed
+	ConditionModeratelyPlayed
+	ConditionHeavilyPlayed
+	ConditionDamaged
+)
+
+var conditionAbbreviations = map[int]string{
+	ConditionNearMint:         "NM",
+	ConditionLightlyPlayed:    "LP",
+	ConditionModeratelyPlayed: "MP",
+	ConditionHeavilyPlayed:    "HP",
+	ConditionDamaged:          "DMG",
+}
+
+// Return the abbreviation (NM, LP, MP, HP, DMG) of a standard condition id,
+// or an empty string if unknown. This is based on the Magic condition ids,
+// and it may not match other categories: use ListCategoryConditions for them.
+func ConditionAbbreviation(conditionId int) string {
+	return conditionAbbreviations[conditionId]
+}
+
+// Return the standard condition id of an abbreviation, ignoring case.
+// This is based on the Magic condition ids, and it may not match other
+// categories: use ListCategoryConditions for them.
+func ConditionIdFromAbbreviation(abbrev string) (int, bool) {
+	for conditionId, abbreviation := range conditionAbbreviations {
+		if strings.EqualFold(abbreviation, abbrev) {
+			return conditionId, true
+		}
+	}
+	return 0, false
+}
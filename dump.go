@@ -0,0 +1,151 @@
+package tcgplayer
+
+import (
+	"sort"
+	"sync"
+)
+
+type DumpOptions struct {
+	// Product types to retrieve, no filter is applied if nil
+	ProductTypes []string
+	// Whether to retrieve the SKUs of each product
+	IncludeSkus bool
+	// How many pages are requested at the same time, 8 if not set
+	Concurrency int
+}
+
+type CategoryDump struct {
+	Category Category  `json:"category"`
+	Groups   []Group   `json:"groups"`
+	Products []Product `json:"products"`
+}
+
+// Retrieve the details, all the groups, and all the products of a category,
+// with groups and products sorted by id. If the Client context is cancelled,
+// whatever was retrieved so far is returned along with the context error.
+func (tcg *Client) DumpCategory(category int, opts DumpOptions) (*CategoryDump, error) {
+	details, err := tcg.GetCategory(category)
+	if err != nil {
+		return nil, err
+	}
+	totalGroups, err := tcg.TotalGroups(category)
+	if err != nil {
+		return nil, err
+	}
+	totalProducts, err := tcg.TotalProducts(category, opts.ProductTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	type page struct {
+		groups bool
+		offset int
+	}
+	var pages []page
+	for i := 0; i < totalGroups; i += MaxItemsInResponse {
+		pages = append(pages, page{groups: true, offset: i})
+	}
+	for i := 0; i < totalProducts; i += MaxItemsInResponse {
+		pages = append(pages, page{offset: i})
+	}
+
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = 8
+	}
+
+	dump := CategoryDump{
+		Category: *details,
+	}
+	jobs := make(chan page)
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range jobs {
+				var groups []Group
+				var products []Product
+				var err error
+				if page.groups {
+					groups, err = tcg.ListAllCategoryGroups(category, page.offset)
+				} else {
+					products, err = tcg.ListAllProducts(category, opts.ProductTypes, opts.IncludeSkus, page.offset)
+				}
+
+				mtx.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				dump.Groups = append(dump.Groups, groups...)
+				dump.Products = append(dump.Products, products...)
+				mtx.Unlock()
+			}
+		}()
+	}
+
+loop:
+	for _, page := range pages {
+		select {
+		case jobs <- page:
+		case <-tcg.ctx.Done():
+			break loop
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(dump.Groups, func(i, j int) bool {
+		return dump.Groups[i].GroupID < dump.Groups[j].GroupID
+	})
+	sort.Slice(dump.Products, func(i, j int) bool {
+		return dump.Products[i].ProductId < dump.Products[j].ProductId
+	})
+
+	// Report cancellation over any error it may have caused
+	if tcg.ctx.Err() != nil {
+		return &dump, tcg.ctx.Err()
+	}
+	return &dump, firstErr
+}
+
+// Retrieve about n products of a category, taken from pages at evenly spaced
+// offsets across the whole catalog, so that only a fraction of the pages is
+// requested. This is a deterministic sample, not a random one: the same
+// products are returned as long as the catalog does not change.
+func (tcg *Client) SampleProducts(category int, n int, productTypes []string) ([]Product, error) {
+	total, err := tcg.TotalProducts(category, productTypes)
+	if err != nil {
+		return nil, err
+	}
+	if n > total {
+		n = total
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+
+	pages := (n + MaxItemsInResponse - 1) / MaxItemsInResponse
+	step := total / pages
+	// Avoid overlapping pages when the sample covers most of the catalog
+	if step < MaxItemsInResponse {
+		step = MaxItemsInResponse
+	}
+
+	out := make([]Product, 0, n)
+	for i := 0; i < pages && len(out) < n; i++ {
+		products, err := tcg.ListAllProducts(category, productTypes, false, i*step)
+		if err != nil {
+			return nil, err
+		}
+		if len(products) > n-len(out) {
+			products = products[:n-len(out)]
+		}
+		out = append(out, products...)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,86 @@
+package tcgplayer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Returned when the API reports a failed request, carrying the HTTP status
+// and the error messages of the response. An APIError with a 404 status
+// matches ErrNotFound with errors.Is.
+type APIError struct {
+	HTTPStatus int
+	Errors     []string
+	URL        string
+}
+
+func (e *APIError) Error() string {
+	return strings.Join(e.Errors, " ")
+}
+
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && e.HTTPStatus == http.StatusNotFound
+}
+
+// Whether err is an APIError for a resource that does not exist, or any
+// other not found error of this package
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// Returned when the API still rejects a request for exceeding the rate limits
+// after all the retries, with the delay suggested by the API, if any, before
+// trying again. It unwraps to an APIError with a 429 status.
+type RateLimitError struct {
+	RetryAfter time.Duration
+	Err        *APIError
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s, retry after %s", e.Err, e.RetryAfter)
+	}
+	return e.Err.Error()
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+func newRateLimitError(resp *http.Response, link string) *RateLimitError {
+	return &RateLimitError{
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		Err: &APIError{
+			HTTPStatus: resp.StatusCode,
+			Errors:     []string{resp.Status},
+			URL:        link,
+		},
+	}
+}
+
+// Parse a Retry-After header, either in seconds or as a date, returning zero
+// if missing or invalid
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(value)
+	if err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	date, err := http.ParseTime(value)
+	if err == nil && time.Until(date) > 0 {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// Whether err is an APIError for a request rejected by the API rate limits
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.HTTPStatus == http.StatusTooManyRequests
+}
@@ -0,0 +1,64 @@
+package tcgplayer
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimitError(t *testing.T) {
+	retryAfter := "30"
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", retryAfter)
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	tcg := stub.client(WithRetriesDisabled())
+
+	_, err := tcg.GetCategory(CategoryMagic)
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("expected a RateLimitError, got %v", err)
+	}
+	if rateErr.RetryAfter != 30*time.Second {
+		t.Errorf("retry after %s, expected 30s", rateErr.RetryAfter)
+	}
+	if !IsRateLimited(err) {
+		t.Error("expected the error to unwrap to a 429 APIError")
+	}
+
+	// The header may also be an HTTP date, only precise to the second
+	retryAfter = time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	_, err = tcg.GetCategory(CategoryMagic)
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("expected a RateLimitError, got %v", err)
+	}
+	if rateErr.RetryAfter < 58*time.Second || rateErr.RetryAfter > time.Minute {
+		t.Errorf("retry after %s, expected about a minute", rateErr.RetryAfter)
+	}
+}
+
+func TestServerError(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`{"success": false, "errors": [], "results": []}`))
+	})
+	tcg := stub.client(WithRetriesDisabled())
+
+	_, err := tcg.GetCategory(CategoryMagic)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an APIError, got %v", err)
+	}
+	if apiErr.HTTPStatus != http.StatusBadGateway {
+		t.Errorf("unexpected status %d", apiErr.HTTPStatus)
+	}
+	if len(apiErr.Errors) == 0 {
+		t.Error("expected the status to be reported when the response has no errors")
+	}
+	var rateErr *RateLimitError
+	if errors.As(err, &rateErr) || IsRateLimited(err) {
+		t.Error("a server error should not be reported as rate limited")
+	}
+}
@@ -2,9 +2,8 @@ module github.com/mtgban/go-tcgplayer
 
 go 1.19
 
-require github.com/hashicorp/go-cleanhttp v0.5.2
-
 require (
-	github.com/hashicorp/go-retryablehttp v0.7.7 // indirect
-	golang.org/x/time v0.6.0 // indirect
+	github.com/hashicorp/go-cleanhttp v0.5.2
+	github.com/hashicorp/go-retryablehttp v0.7.7
+	golang.org/x/time v0.6.0
 )
@@ -0,0 +1,66 @@
+package tcgplayer
+
+// Iterate over all the products of a category, requesting one page of
+// MaxItemsInResponse products at a time, only when the previous one is
+// exhausted. No background work is performed, so an iterator can be
+// abandoned at any time.
+type ProductIterator struct {
+	tcg   *Client
+	query productQuery
+
+	page  []Product
+	total int
+	done  bool
+	err   error
+}
+
+// Return an iterator over all the products of a category, with extended
+// fields, and with SKUs if includeSkus is set
+func (tcg *Client) ProductsIterator(category int, productTypes []string, includeSkus bool) *ProductIterator {
+	return &ProductIterator{
+		tcg: tcg,
+		query: productQuery{
+			category:     category,
+			productTypes: productTypes,
+			includeSkus:  includeSkus,
+			extended:     true,
+		},
+		total: -1,
+	}
+}
+
+// Return the next product, or false once all products were returned or a
+// request failed, in which case Err reports the error
+func (it *ProductIterator) Next() (Product, bool) {
+	for len(it.page) == 0 {
+		if it.done || (it.total >= 0 && it.query.offset >= it.total) {
+			it.done = true
+			return Product{}, false
+		}
+
+		products, resp, err := it.tcg.listProducts(it.query)
+		if err != nil {
+			it.err = err
+			it.done = true
+			return Product{}, false
+		}
+		it.total = resp.TotalItems
+		it.query.offset += MaxItemsInResponse
+
+		// Avoid looping forever if the catalog shrinks while iterating
+		if len(products) == 0 {
+			it.done = true
+			return Product{}, false
+		}
+		it.page = products
+	}
+
+	product := it.page[0]
+	it.page = it.page[1:]
+	return product, true
+}
+
+// Return the error that stopped the iteration, if any
+func (it *ProductIterator) Err() error {
+	return it.err
+}
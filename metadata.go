@@ -0,0 +1,148 @@
+package tcgplayer
+
+import (
+	"sync"
+)
+
+// All the metadata needed to decode the SKUs of a category
+type CategoryMetadata struct {
+	Conditions []Condition `json:"conditions"`
+	Languages  []Language  `json:"languages"`
+	Printings  []Printing  `json:"printings"`
+	Rarities   []Rarity    `json:"rarities"`
+
+	// Names indexed by their respective ids
+	ConditionNames map[int]string `json:"-"`
+	LanguageNames  map[int]string `json:"-"`
+	PrintingNames  map[int]string `json:"-"`
+	RarityNames    map[int]string `json:"-"`
+}
+
+// Retrieve conditions, languages, printings, and rarities of a category
+// concurrently. The result is cached for the lifetime of the Client, so only
+// the first call for a given category performs any request.
+func (tcg *Client) GetCategoryMetadata(category int) (*CategoryMetadata, error) {
+	tcg.cache.mtx.RLock()
+	metadata, found := tcg.cache.metadata[category]
+	tcg.cache.mtx.RUnlock()
+	if found {
+		return metadata, nil
+	}
+
+	metadata = &CategoryMetadata{}
+	var errs [4]error
+	var wg sync.WaitGroup
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		metadata.Conditions, errs[0] = tcg.ListCategoryConditions(category)
+	}()
+	go func() {
+		defer wg.Done()
+		metadata.Languages, errs[1] = tcg.ListCategoryLanguages(category)
+	}()
+	go func() {
+		defer wg.Done()
+		metadata.Printings, errs[2] = tcg.ListCategoryPrintings(category)
+	}()
+	go func() {
+		defer wg.Done()
+		metadata.Rarities, errs[3] = tcg.ListCategoryRarities(category)
+	}()
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	metadata.ConditionNames = map[int]string{}
+	for _, condition := range metadata.Conditions {
+		metadata.ConditionNames[condition.ConditionId] = condition.Name
+	}
+	metadata.LanguageNames = map[int]string{}
+	for _, language := range metadata.Languages {
+		metadata.LanguageNames[language.LanguageId] = language.Name
+	}
+	metadata.PrintingNames = map[int]string{}
+	for _, printing := range metadata.Printings {
+		metadata.PrintingNames[printing.PrintingId] = printing.Name
+	}
+	metadata.RarityNames = map[int]string{}
+	for _, rarity := range metadata.Rarities {
+		metadata.RarityNames[rarity.RarityId] = rarity.DisplayText
+	}
+
+	tcg.cache.mtx.Lock()
+	tcg.cache.metadata[category] = metadata
+	tcg.cache.conditions[category] = metadata.Conditions
+	tcg.cache.printings[category] = metadata.Printings
+	tcg.cache.mtx.Unlock()
+
+	return metadata, nil
+}
+
+// Load and cache the metadata of a category, so that the first SKU decoding
+// does not need to wait for it
+func (tcg *Client) WarmCategory(category int) error {
+	_, err := tcg.GetCategoryMetadata(category)
+	return err
+}
+
+type warmCategory struct {
+	category int
+	wait     bool
+}
+
+// Load the metadata of the categories requested with WithWarmCategory,
+// logging any failure, since construction cannot return errors
+func (tcg *Client) warmCategories() {
+	for _, warm := range tcg.warm {
+		category := warm.category
+		load := func() {
+			err := tcg.WarmCategory(category)
+			if err != nil && tcg.logger != nil {
+				tcg.logger.Printf("[ERR] unable to warm category %d: %s", category, err)
+			}
+		}
+		if warm.wait {
+			load()
+		} else {
+			go load()
+		}
+	}
+}
+
+// Return the name of a condition of a category, such as "Near Mint", or false
+// if the id is unknown. The category metadata is requested on first use only.
+func (tcg *Client) ConditionName(category, conditionId int) (string, bool, error) {
+	metadata, err := tcg.GetCategoryMetadata(category)
+	if err != nil {
+		return "", false, err
+	}
+	name, found := metadata.ConditionNames[conditionId]
+	return name, found, nil
+}
+
+// Return the name of a language of a category, such as "English", or false
+// if the id is unknown. The category metadata is requested on first use only.
+func (tcg *Client) LanguageName(category, languageId int) (string, bool, error) {
+	metadata, err := tcg.GetCategoryMetadata(category)
+	if err != nil {
+		return "", false, err
+	}
+	name, found := metadata.LanguageNames[languageId]
+	return name, found, nil
+}
+
+// Return the name of a printing of a category, such as "Foil", or false if
+// the id is unknown. The category metadata is requested on first use only.
+func (tcg *Client) PrintingName(category, printingId int) (string, bool, error) {
+	metadata, err := tcg.GetCategoryMetadata(category)
+	if err != nil {
+		return "", false, err
+	}
+	name, found := metadata.PrintingNames[printingId]
+	return name, found, nil
+}
@@ -0,0 +1,52 @@
+package tcgplayer
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// Outcome of an API call, reported to the function set with WithObserver
+type RequestInfo struct {
+	Method string
+	URL    string
+
+	// Zero if no response was received
+	HTTPStatus int
+
+	// Total time of the call, including retries and rate limiting
+	Duration time.Duration
+
+	// How many times the request was retried
+	Retries int
+
+	// Size of the response body that was read
+	Bytes int64
+
+	Err error
+}
+
+type retriesKey struct{}
+
+// Count the bytes read from a response body
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Record the attempt number of each request in the counter carried by its
+// context, if any
+func countRetries(_ retryablehttp.Logger, req *http.Request, attempt int) {
+	retries, ok := req.Context().Value(retriesKey{}).(*int)
+	if ok {
+		*retries = attempt
+	}
+}
@@ -0,0 +1,261 @@
+package tcgplayer
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"golang.org/x/time/rate"
+)
+
+// Customize a Client during NewClient or Clone
+type ClientOption func(*Client)
+
+// Use a new rate limiter with the given rate and burst size
+func WithRateLimit(r rate.Limit, b int) ClientOption {
+	return func(tcg *Client) {
+		tcg.limiter = rate.NewLimiter(r, b)
+	}
+}
+
+// Log requests and retries with the given logger, none by default.
+// Credentials and tokens are always redacted from the logged requests.
+func WithLogger(logger retryablehttp.Logger) ClientOption {
+	return func(tcg *Client) {
+		tcg.logger = logger
+	}
+}
+
+// Perform every request with the given context, so that cancelling it
+// interrupts any in-flight call of the Client
+func WithContext(ctx context.Context) ClientOption {
+	return func(tcg *Client) {
+		tcg.ctx = ctx
+	}
+}
+
+// Bound every API call to the given duration, including any retry and the
+// time spent waiting for the rate limiter, on top of the WithContext context.
+// Helpers performing several calls, such as the batch or GetAll methods,
+// apply it to each call separately: use a context with a deadline to bound
+// them as a whole.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(tcg *Client) {
+		tcg.timeout = timeout
+	}
+}
+
+// Send every request to the given host, such as "http://127.0.0.1:8080",
+// instead of the TCGplayer one, for example to use a local stub server in
+// tests or a caching reverse proxy. Paths are kept unchanged, including the
+// API version, and tokens are requested from the "/token" path of the host
+// too, unless WithTokenURL is set.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(tcg *Client) {
+		tcg.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// Request tokens from the given URL instead of the TCGplayer one, for
+// example to authenticate against a local stub server in tests
+func WithTokenURL(tokenURL string) ClientOption {
+	return func(tcg *Client) {
+		tcg.tokenURL = tokenURL
+	}
+}
+
+// Send requests through the given http client, for example to set a proxy or
+// a custom transport. The client is copied, and its transport is wrapped to
+// add authentication and rate limiting, so the original is never modified.
+// Its own timeout, if any, applies to each attempt rather than to the whole
+// call, see WithTimeout for that.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(tcg *Client) {
+		tcg.httpClient = client
+	}
+}
+
+// Send requests through the given RoundTripper, for example an instrumented
+// or proxying transport, which is wrapped to add authentication and rate
+// limiting. It replaces the transport of any client set with WithHTTPClient.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(tcg *Client) {
+		tcg.transport = transport
+	}
+}
+
+// Load and save tokens with the given store, so that they can be reused
+// across restarts, instead of only keeping them in memory. Only one token
+// request or store access happens at a time, across clones too.
+func WithTokenStore(store TokenStore) ClientOption {
+	return func(tcg *Client) {
+		tcg.tokenStore = store
+	}
+}
+
+// Replace tokens the given duration before they expire, one hour by default.
+// Tokens are valid for the expires_in seconds reported by the token endpoint,
+// usually about two weeks, so a lead longer than that requests a new token
+// every time. Negative values are ignored.
+func WithTokenRefreshLead(lead time.Duration) ClientOption {
+	return func(tcg *Client) {
+		if lead < 0 {
+			return
+		}
+		tcg.tokenRefreshLead = lead
+	}
+}
+
+// Make NewClientValidated request a token, so that invalid keys are reported
+// at construction rather than on the first call. It has no effect on NewClient.
+func WithCredentialsCheck() ClientOption {
+	return func(tcg *Client) {
+		tcg.checkCredentials = true
+	}
+}
+
+// Send the given User-Agent with every request, for example to identify the
+// application using this package
+func WithUserAgent(userAgent string) ClientOption {
+	return func(tcg *Client) {
+		tcg.userAgent = userAgent
+	}
+}
+
+// Call fn after each API call, successful or not, with its URL, status,
+// duration, retries and response size, for example to export metrics.
+// It may be called concurrently, and should return quickly.
+func WithObserver(fn func(RequestInfo)) ClientOption {
+	return func(tcg *Client) {
+		tcg.observer = fn
+	}
+}
+
+// Use a custom TLS configuration for every connection, for example to trust
+// the certificate of an intercepting proxy
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(tcg *Client) {
+		tcg.tlsConfig = config
+	}
+}
+
+// Disable TLS certificate verification.
+//
+// WARNING: this is INSECURE, any connection becomes vulnerable to
+// man-in-the-middle attacks, and the API credentials and token could be
+// stolen. Only use it in sandboxes or behind a trusted intercepting proxy,
+// and prefer WithTLSConfig with the proxy certificate whenever possible.
+func WithInsecureSkipVerify() ClientOption {
+	return func(tcg *Client) {
+		config := &tls.Config{}
+		if tcg.tlsConfig != nil {
+			config = tcg.tlsConfig.Clone()
+		}
+		config.InsecureSkipVerify = true
+		tcg.tlsConfig = config
+	}
+}
+
+// Drop duplicate products returned in the same page of results, which may
+// happen while the catalog is being edited, and log them with the logger
+func WithDedupeProducts() ClientOption {
+	return func(tcg *Client) {
+		tcg.dedupeProducts = true
+	}
+}
+
+// Request prices again, up to n times, when the pricing endpoints return no
+// results for a non-empty list of ids, which sometimes happens transiently.
+// Each attempt waits with the same backoff used for failed requests.
+// If results are still empty after all the retries, prices are considered
+// genuinely missing, and an empty slice is returned without error.
+func WithRetryOnEmpty(n int) ClientOption {
+	return func(tcg *Client) {
+		tcg.emptyRetries = n
+	}
+}
+
+// Refuse any request beyond n in the last 24 hours, returning ErrBudgetExceeded.
+// The window is sliding, so budget is freed as old requests leave it.
+// When used with Clone, the new Client gets its own separate budget.
+func WithDailyRequestBudget(n int) ClientOption {
+	return func(tcg *Client) {
+		tcg.budget = &requestBudget{
+			limit: n,
+		}
+	}
+}
+
+// Set the maximum number of idle connections kept open across all hosts,
+// 100 by default
+func WithMaxIdleConns(n int) ClientOption {
+	return func(tcg *Client) {
+		tcg.maxIdleConns = n
+	}
+}
+
+// Set the maximum number of idle connections kept open to the API host, by
+// default one more than GOMAXPROCS. It should be at least as high as the
+// number of concurrent workers, for example 8 for the default tcgdumper.
+func WithMaxIdleConnsPerHost(n int) ClientOption {
+	return func(tcg *Client) {
+		tcg.maxIdleConnsPerHost = n
+	}
+}
+
+// Make the batch helpers stop at the first failed chunk, cancelling any
+// request in progress, instead of completing the remaining chunks
+func WithFailFast(enabled bool) ClientOption {
+	return func(tcg *Client) {
+		tcg.failFast = enabled
+	}
+}
+
+// Set how many chunks the batch helpers request at the same time, 4 by
+// default. All the requests still share the Client rate limiter.
+func WithBatchConcurrency(n int) ClientOption {
+	return func(tcg *Client) {
+		tcg.batchConcurrency = n
+	}
+}
+
+// Decide whether a request should be retried, replacing the default policy,
+// which retries connection errors, 429 and 5xx responses. A non-nil error
+// stops retrying and is returned to the caller. The policy only decides if
+// a retry happens: the number of attempts is still bounded by the client
+// retry count, and a cancelled context always stops retrying.
+func WithRetryPolicy(policy func(resp *http.Response, err error) (bool, error)) ClientOption {
+	return func(tcg *Client) {
+		tcg.retryPolicy = policy
+	}
+}
+
+// Retry failed requests up to n times, 4 by default
+func WithRetryMax(n int) ClientOption {
+	return func(tcg *Client) {
+		tcg.retryMax = n
+	}
+}
+
+// Never retry failed requests, so that errors are returned immediately
+func WithRetriesDisabled() ClientOption {
+	return func(tcg *Client) {
+		tcg.retryMax = 0
+	}
+}
+
+// Load and cache the metadata (conditions, languages, printings, rarities)
+// of a category when the Client is created, in the background, or blocking
+// the construction if wait is set. Failures are only logged, and the
+// metadata is requested again on first use.
+func WithWarmCategory(category int, wait bool) ClientOption {
+	return func(tcg *Client) {
+		tcg.warm = append(tcg.warm, warmCategory{
+			category: category,
+			wait:     wait,
+		})
+	}
+}
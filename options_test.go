@@ -0,0 +1,60 @@
+package tcgplayer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithTokenURL(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("grant_type") != "client_credentials" ||
+			r.PostForm.Get("client_id") != "public" ||
+			r.PostForm.Get("client_secret") != "private" {
+			http.Error(w, `{"error":"invalid_client"}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"fake-token","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	var authorization string
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		writeResults(w, 1, []Category{{CategoryID: CategoryMagic}})
+	})
+
+	_, err := stub.client(WithTokenURL(tokenServer.URL)).GetCategory(CategoryMagic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authorization != "Bearer fake-token" {
+		t.Errorf("unexpected Authorization header %q", authorization)
+	}
+	if n := stub.tokenRequests.Load(); n != 0 {
+		t.Errorf("requested %d tokens from the default endpoint", n)
+	}
+}
+
+func TestWithRetriesDisabled(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+
+	tcg := stub.client(WithRetriesDisabled())
+	start := time.Now()
+	_, err := tcg.GetCategory(CategoryMagic)
+	if err == nil {
+		t.Fatal("expected the request to fail")
+	}
+	if n := stub.requests.Load(); n != 1 {
+		t.Errorf("sent %d requests, expected 1", n)
+	}
+	// Any retry would wait at least RetryWaitMin
+	if elapsed := time.Since(start); elapsed >= tcg.client.RetryWaitMin {
+		t.Errorf("request took %s, expected no retry wait", elapsed)
+	}
+}
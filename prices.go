@@ -0,0 +1,154 @@
+package tcgplayer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Return the value of a price, or zero if not available
+func priceValue(price *float64) float64 {
+	if price == nil {
+		return 0
+	}
+	return *price
+}
+
+func (p ProductPriceSet) Low() float64       { return priceValue(p.LowPrice) }
+func (p ProductPriceSet) Mid() float64       { return priceValue(p.MidPrice) }
+func (p ProductPriceSet) High() float64      { return priceValue(p.HighPrice) }
+func (p ProductPriceSet) Market() float64    { return priceValue(p.MarketPrice) }
+func (p ProductPriceSet) DirectLow() float64 { return priceValue(p.DirectLowPrice) }
+
+func (p SKUPriceSet) Low() float64           { return priceValue(p.LowPrice) }
+func (p SKUPriceSet) Shipping() float64      { return priceValue(p.LowestShipping) }
+func (p SKUPriceSet) LowestListing() float64 { return priceValue(p.LowestListingPrice) }
+func (p SKUPriceSet) Market() float64        { return priceValue(p.MarketPrice) }
+func (p SKUPriceSet) DirectLow() float64     { return priceValue(p.DirectLowPrice) }
+
+func (p BuylistPrices) High() float64   { return priceValue(p.HighPrice) }
+func (p BuylistPrices) Market() float64 { return priceValue(p.MarketPrice) }
+
+// Return the total cost of the cheapest listing, shipping included, or false
+// if there is no listing
+func (p SKUPriceSet) LandedLowest() (float64, bool) {
+	if p.LowestListingPrice == nil {
+		return 0, false
+	}
+	return p.LowestListing() + p.Shipping(), true
+}
+
+// Return how much cheaper the Direct listing is compared to the market price,
+// a negative value means Direct is more expensive, zero is returned if either
+// price is missing
+func (p ProductPriceSet) DirectDiscount() float64 {
+	if p.Market() == 0 || p.DirectLow() == 0 {
+		return 0
+	}
+	return p.Market() - p.DirectLow()
+}
+
+// Keep only the price sets where Direct undercuts the market price by more
+// than the given threshold
+func FilterDirectDiscounts(prices []ProductPriceSet, threshold float64) []ProductPriceSet {
+	var out []ProductPriceSet
+	for _, price := range prices {
+		if price.DirectDiscount() > threshold {
+			out = append(out, price)
+		}
+	}
+	return out
+}
+
+// Group price sets by product id and then by subtype name (Normal, Foil...)
+func NormalizePrices(prices []ProductPriceSet) map[int]map[string]ProductPriceSet {
+	out := map[int]map[string]ProductPriceSet{}
+	for _, price := range prices {
+		_, found := out[price.ProductId]
+		if !found {
+			out[price.ProductId] = map[string]ProductPriceSet{}
+		}
+		out[price.ProductId][price.SubTypeName] = price
+	}
+	return out
+}
+
+type PriceChange struct {
+	ProductId int
+	OldPrice  float64
+	NewPrice  float64
+	// Relative change, in percent, negative for a price drop
+	PercentChange float64
+}
+
+// Compare the market prices of two snapshots, returning the products whose
+// price moved by more than pctThreshold percent in either direction, sorted
+// from the biggest mover. Products missing from either snapshot, or without
+// a market price in either of them, are skipped.
+func PriceChanges(prev, curr map[int]ProductPriceSet, pctThreshold float64) []PriceChange {
+	var out []PriceChange
+	for productId, price := range curr {
+		old, found := prev[productId]
+		if !found || old.Market() == 0 || price.MarketPrice == nil {
+			continue
+		}
+
+		pct := (price.Market() - old.Market()) / old.Market() * 100
+		if math.Abs(pct) <= pctThreshold {
+			continue
+		}
+		out = append(out, PriceChange{
+			ProductId:     productId,
+			OldPrice:      old.Market(),
+			NewPrice:      price.Market(),
+			PercentChange: pct,
+		})
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		a, b := math.Abs(out[i].PercentChange), math.Abs(out[j].PercentChange)
+		if a == b {
+			return out[i].ProductId < out[j].ProductId
+		}
+		return a > b
+	})
+
+	return out
+}
+
+// Retrieve the cheapest copy of a product available for purchase, regardless
+// of its condition, comparing the landed cost of the lowest listing of each
+// SKU. If none of the SKUs is listed, ErrNotFound is returned.
+func (tcg *Client) GetLowestAvailablePrice(productId int) (SKUPriceSet, SKU, error) {
+	skus, err := tcg.ListProductSKUs(productId)
+	if err != nil {
+		return SKUPriceSet{}, SKU{}, err
+	}
+
+	skuIds := make([]int, 0, len(skus))
+	index := make(map[int]SKU, len(skus))
+	for _, sku := range skus {
+		skuIds = append(skuIds, sku.SkuId)
+		index[sku.SkuId] = sku
+	}
+
+	prices, err := tcg.GetAllMarketPricesBySKUs(skuIds)
+	if err != nil {
+		return SKUPriceSet{}, SKU{}, err
+	}
+
+	var best SKUPriceSet
+	bestPrice := math.Inf(1)
+	for _, price := range prices {
+		landed, found := price.LandedLowest()
+		if found && landed < bestPrice {
+			best = price
+			bestPrice = landed
+		}
+	}
+	if math.IsInf(bestPrice, 1) {
+		return SKUPriceSet{}, SKU{}, fmt.Errorf("listings for product %d: %w", productId, ErrNotFound)
+	}
+
+	return best, index[best.SkuId], nil
+}
@@ -0,0 +1,215 @@
+package tcgplayer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Reply to a SKU pricing request with a market price equal to each SKU id,
+// returning the requested ids
+func writeSKUPrices(w http.ResponseWriter, r *http.Request) []int {
+	var ids []int
+	var prices []SKUPriceSet
+	for _, field := range strings.Split(strings.TrimPrefix(r.URL.Path, "/pricing/sku/"), ",") {
+		id, _ := strconv.Atoi(field)
+		price := float64(id)
+		ids = append(ids, id)
+		prices = append(prices, SKUPriceSet{SkuId: id, MarketPrice: &price})
+	}
+	writeResults(w, len(prices), prices)
+	return ids
+}
+
+// Create a Client retrying empty prices without waiting too long
+func retryOnEmptyClient(stub *stubServer, n int) *Client {
+	tcg := stub.client(WithRetryOnEmpty(n))
+	tcg.client.RetryWaitMin = time.Millisecond
+	tcg.client.RetryWaitMax = 10 * time.Millisecond
+	return tcg
+}
+
+func TestRetryOnEmpty(t *testing.T) {
+	var calls atomic.Int32
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// The first response is transiently empty
+		if calls.Add(1) == 1 {
+			writeResults(w, 0, []SKUPriceSet{})
+			return
+		}
+		writeSKUPrices(w, r)
+	})
+
+	prices, err := retryOnEmptyClient(stub, 2).GetMarketPricesBySKUs([]int{42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != 1 || prices[0].Market() != 42 {
+		t.Errorf("unexpected prices %+v", prices)
+	}
+	if n := stub.requests.Load(); n != 2 {
+		t.Errorf("sent %d requests, expected 2", n)
+	}
+}
+
+func TestRetryOnEmptyMissing(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		writeResults(w, 0, []SKUPriceSet{})
+	})
+
+	prices, err := retryOnEmptyClient(stub, 2).GetMarketPricesBySKUs([]int{42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != 0 {
+		t.Errorf("unexpected prices %+v", prices)
+	}
+	if n := stub.requests.Load(); n != 3 {
+		t.Errorf("sent %d requests, expected 3", n)
+	}
+}
+
+func TestRetryOnEmptyCanceled(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		writeResults(w, 0, []SKUPriceSet{})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	// The default backoff waits at least one second before the first retry
+	_, err := stub.client(WithRetryOnEmpty(2), WithContext(ctx)).GetMarketPricesBySKUs([]int{42})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the retries to stop at the deadline, got %v", err)
+	}
+	if n := stub.requests.Load(); n != 1 {
+		t.Errorf("sent %d requests, expected 1", n)
+	}
+}
+func TestPriceSetMarshal(t *testing.T) {
+	zero := 0.0
+	market := 1.5
+	data, err := json.Marshal(SKUPriceSet{
+		SkuId:       1,
+		LowPrice:    &zero,
+		MarketPrice: &market,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"skuId":1,"lowPrice":0,"marketPrice":1.5}`
+	if string(data) != expected {
+		t.Errorf("got %s, expected %s", data, expected)
+	}
+
+	var price ProductPriceSet
+	err = json.Unmarshal([]byte(`{"productId":1,"lowPrice":null,"marketPrice":0,"subTypeName":"Foil"}`), &price)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.LowPrice != nil || price.HighPrice != nil {
+		t.Errorf("missing prices should be nil: %+v", price)
+	}
+	if price.MarketPrice == nil || price.Market() != 0 {
+		t.Errorf("a zero market price should be kept: %+v", price)
+	}
+	if price.Low() != 0 || price.High() != 0 {
+		t.Errorf("missing prices should read as zero: %+v", price)
+	}
+}
+
+func TestNormalizePrices(t *testing.T) {
+	normal, foil := 1.0, 5.0
+	index := NormalizePrices([]ProductPriceSet{
+		{ProductId: 1, SubTypeName: "Normal", MarketPrice: &normal},
+		{ProductId: 2, SubTypeName: "Normal", MarketPrice: &normal},
+		{ProductId: 2, SubTypeName: "Foil", MarketPrice: &foil},
+	})
+
+	if len(index) != 2 {
+		t.Fatalf("got %d products, expected 2", len(index))
+	}
+	if len(index[1]) != 1 || index[1]["Normal"].Market() != normal {
+		t.Errorf("unexpected subtypes for a single subtype product: %+v", index[1])
+	}
+	if len(index[2]) != 2 || index[2]["Normal"].Market() != normal || index[2]["Foil"].Market() != foil {
+		t.Errorf("unexpected subtypes for a multiple subtypes product: %+v", index[2])
+	}
+}
+
+func TestProductPricesPayload(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"success": true,
+			"errors": [],
+			"results": [
+				{"productId": 1234, "lowPrice": 0.05, "midPrice": 0.25, "highPrice": 4.99, "marketPrice": 0.18, "directLowPrice": null, "subTypeName": "Normal"},
+				{"productId": 1234, "lowPrice": 0.4, "midPrice": 1.1, "highPrice": 9.99, "marketPrice": 0.95, "directLowPrice": 0.89, "subTypeName": "Foil"}
+			]
+		}`))
+	})
+
+	prices, err := stub.client().GetMarketPricesByProducts([]int{1234})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != 2 {
+		t.Fatalf("got %d price sets, expected 2", len(prices))
+	}
+	if prices[0].High() != 4.99 || prices[0].Mid() != 0.25 || prices[0].DirectLowPrice != nil {
+		t.Errorf("unexpected Normal prices %+v", prices[0])
+	}
+	if prices[1].High() != 9.99 || prices[1].DirectLow() != 0.89 || prices[1].SubTypeName != "Foil" {
+		t.Errorf("unexpected Foil prices %+v", prices[1])
+	}
+}
+
+func TestGetMarketPricesByGroup(t *testing.T) {
+	// Group 1 fits in a single page, group 2 takes two
+	groups := map[string][]ProductPriceSet{
+		"/pricing/group/1": {{ProductId: 1, SubTypeName: "Normal"}, {ProductId: 1, SubTypeName: "Foil"}},
+		"/pricing/group/2": {{ProductId: 2, SubTypeName: "Normal"}, {ProductId: 3, SubTypeName: "Normal"}, {ProductId: 4, SubTypeName: "Normal"}},
+	}
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		prices, found := groups[r.URL.Path]
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		end := offset + 2
+		if end > len(prices) {
+			end = len(prices)
+		}
+		writeResults(w, len(prices), prices[offset:end])
+	})
+	tcg := stub.client()
+
+	prices, err := tcg.GetMarketPricesByGroup(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != 2 || stub.requests.Load() != 1 {
+		t.Errorf("got %d prices with %d requests, expected 2 with a single request", len(prices), stub.requests.Load())
+	}
+
+	stub.requests.Store(0)
+	prices, err = tcg.GetMarketPricesByGroup(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != 3 || stub.requests.Load() != 2 {
+		t.Errorf("got %d prices with %d requests, expected 3 with two requests", len(prices), stub.requests.Load())
+	}
+	for i, price := range prices {
+		if price.ProductId != i+2 {
+			t.Errorf("price %d is for product %d, expected %d", i, price.ProductId, i+2)
+		}
+	}
+}
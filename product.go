@@ -0,0 +1,141 @@
+package tcgplayer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	tcgImageURL = "https://tcgplayer-cdn.tcgplayer.com/product/%d_%dw.jpg"
+	tcgStoreURL = "https://www.tcgplayer.com/product/%d/%s"
+)
+
+// Whether the API reported an image for the product
+func (p Product) HasImage() bool {
+	return p.ImageUrl != ""
+}
+
+// Build the CDN image URL of a product at the given width in pixels, such as
+// 200 or 400. If the product has no image, an empty string and false are
+// returned, and it is up to the caller to display a placeholder, rather than
+// linking to an image that would not load.
+func ProductImageURL(product Product, width int) (string, bool) {
+	if !product.HasImage() {
+		return "", false
+	}
+	return fmt.Sprintf(tcgImageURL, product.ProductId, width), true
+}
+
+// Accented letters are replaced by their plain counterpart in slugs
+var slugFolding = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+	'ç': "c",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+	'ñ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'œ': "oe",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u",
+	'ý': "y", 'ÿ': "y",
+	'ß': "ss",
+}
+
+// Convert a product name to the slug used in TCGplayer URLs: lowercase,
+// accents removed, apostrophes dropped, and any other run of non-alphanumeric
+// characters replaced by a single hyphen, for example "Urza's Saga" becomes
+// "urzas-saga" and "Jace, the Mind Sculptor" becomes "jace-the-mind-sculptor"
+func CleanNameSlug(name string) string {
+	var sb strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(name) {
+		if r == '\'' || r == '’' {
+			continue
+		}
+
+		text, found := slugFolding[r]
+		if !found && (r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))) {
+			text, found = string(r), true
+		}
+		if !found {
+			pendingHyphen = sb.Len() > 0
+			continue
+		}
+
+		if pendingHyphen {
+			sb.WriteByte('-')
+			pendingHyphen = false
+		}
+		sb.WriteString(text)
+	}
+	return sb.String()
+}
+
+// Return the public store URL of the product, as reported by the API, or
+// built from its id and name if missing
+func (p Product) StoreURL() string {
+	if p.URL != "" {
+		return p.URL
+	}
+	name := p.CleanName
+	if name == "" {
+		name = p.Name
+	}
+	return fmt.Sprintf(tcgStoreURL, p.ProductId, CleanNameSlug(name))
+}
+
+// Keep only the products having at least one SKU with the given printing,
+// such as foil. Products must have been retrieved with their SKUs.
+func FilterProductsWithPrinting(products []Product, printingId int) []Product {
+	var out []Product
+	for _, product := range products {
+		for _, sku := range product.Skus {
+			if sku.PrintingId == printingId {
+				out = append(out, product)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Classify a product as a single or a sealed product from its extended data.
+// The heuristic is that singles always carry a collector number or a rarity,
+// while sealed products do not. Products retrieved without extended fields
+// cannot be classified, and both values are false.
+func ClassifyProduct(product Product) (singles bool, sealed bool) {
+	if len(product.ExtendedData) == 0 {
+		return false, false
+	}
+	_, hasNumber := product.ExtendedValue("Number")
+	_, hasRarity := product.ExtendedValue("Rarity")
+	if hasNumber || hasRarity {
+		return true, false
+	}
+	return false, true
+}
+
+// Compose a display title for labels and exports, such as
+// "Lightning Bolt (Limited Edition Beta) #161", from the product name, the
+// name of its group, or its abbreviation if the name is empty, and its
+// collector number. Any missing piece is left out, and the group is ignored
+// if it is not the one of the product.
+func DisplayTitle(product Product, group Group) string {
+	title := product.Name
+
+	if group.GroupID != 0 && group.GroupID == product.GroupId {
+		set := group.Name
+		if set == "" {
+			set = group.Abbreviation
+		}
+		if set != "" {
+			title += " (" + set + ")"
+		}
+	}
+
+	number, found := product.ExtendedValue("Number")
+	if found && number != "" {
+		title += " #" + number
+	}
+
+	return strings.TrimSpace(title)
+}
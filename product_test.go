@@ -0,0 +1,63 @@
+package tcgplayer
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestCleanNameSlug(t *testing.T) {
+	tests := map[string]string{
+		"Urza's Saga":                   "urzas-saga",
+		"Urza’s Saga":                   "urzas-saga",
+		"Jace, the Mind Sculptor":       "jace-the-mind-sculptor",
+		"Lim-Dûl's Vault":               "lim-duls-vault",
+		"Æther Vial":                    "aether-vial",
+		"Séance":                        "seance",
+		"Fire // Ice":                   "fire-ice",
+		"  Black Lotus  ":               "black-lotus",
+		"Kongming, \"Sleeping Dragon\"": "kongming-sleeping-dragon",
+		"":                              "",
+	}
+	for name, expected := range tests {
+		slug := CleanNameSlug(name)
+		if slug != expected {
+			t.Errorf("slug of %q is %q, expected %q", name, slug, expected)
+		}
+	}
+}
+
+func TestGetAllGroupProducts(t *testing.T) {
+	const total = MaxItemsInResponse + 5
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("groupId") != "42" {
+			t.Errorf("unexpected groupId %q", query.Get("groupId"))
+		}
+		if query.Has("categoryId") {
+			t.Errorf("unexpected categoryId %q", query.Get("categoryId"))
+		}
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		var products []Product
+		for id := offset + 1; id <= total && len(products) < MaxItemsInResponse; id++ {
+			products = append(products, Product{ProductId: id, GroupId: 42})
+		}
+		writeResults(w, total, products)
+	})
+
+	products, err := stub.client().GetAllGroupProducts(42, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(products) != total {
+		t.Fatalf("got %d products, expected %d", len(products), total)
+	}
+	for i, product := range products {
+		if product.ProductId != i+1 {
+			t.Fatalf("product %d has id %d, expected %d", i, product.ProductId, i+1)
+		}
+	}
+	if n := stub.requests.Load(); n != 2 {
+		t.Errorf("sent %d requests, expected 2", n)
+	}
+}
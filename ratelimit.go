@@ -0,0 +1,65 @@
+package tcgplayer
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Latest rate limit counters reported by the API in the response headers
+type rateLimitStatus struct {
+	mtx       sync.Mutex
+	limit     int
+	remaining int
+	reset     time.Time
+	seen      bool
+}
+
+// Record the rate limit headers of a response, if present
+func (s *rateLimitStatus) update(header http.Header) {
+	limit, errLimit := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	remaining, errRemaining := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if errLimit != nil && errRemaining != nil {
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.seen = true
+	if errLimit == nil {
+		s.limit = limit
+	}
+	if errRemaining == nil {
+		s.remaining = remaining
+	}
+	s.reset = parseRateLimitReset(header.Get("X-RateLimit-Reset"))
+}
+
+// The reset header is either a unix timestamp or a number of seconds from now
+func parseRateLimitReset(value string) time.Time {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds <= 0 {
+		return time.Time{}
+	}
+	// Any value past 2001 cannot be a delay
+	if seconds > 1e9 {
+		return time.Unix(seconds, 0)
+	}
+	return time.Now().Add(time.Duration(seconds) * time.Second)
+}
+
+// Return the latest rate limit counters reported by the API, so that long
+// running jobs can slow down before being throttled. Both counters are -1
+// if the API never reported them, and reset is zero if unknown. The counters
+// are shared with any clone of the Client.
+func (tcg *Client) RateLimitStatus() (remaining, limit int, reset time.Time) {
+	tcg.rateLimits.mtx.Lock()
+	defer tcg.rateLimits.mtx.Unlock()
+
+	if !tcg.rateLimits.seen {
+		return -1, -1, time.Time{}
+	}
+	return tcg.rateLimits.remaining, tcg.rateLimits.limit, tcg.rateLimits.reset
+}
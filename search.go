@@ -0,0 +1,79 @@
+package tcgplayer
+
+import (
+	"fmt"
+)
+
+// A filter of a catalog search, matching products having any of the values
+// for the attribute with the given name, such as "ProductName" or "Rarity"
+type SearchFilter struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// Parameters of a catalog search. Sort is one of the sorting options of the
+// search manifest, such as "ProductName ASC", and Limit defaults to
+// MaxItemsInResponse if not set.
+type SearchFilters struct {
+	Sort    string         `json:"sort,omitempty"`
+	Limit   int            `json:"limit"`
+	Offset  int            `json:"offset"`
+	Filters []SearchFilter `json:"filters"`
+}
+
+// Search the products of a category matching all the given filters, returning
+// one page of product ids and the total number of matches, to be used for
+// paging with the Offset of the filters
+func (tcg *Client) SearchProducts(category int, filters SearchFilters) ([]int, int, error) {
+	if filters.Limit <= 0 {
+		filters.Limit = MaxItemsInResponse
+	}
+	if filters.Filters == nil {
+		filters.Filters = []SearchFilter{}
+	}
+
+	link := fmt.Sprintf("%s/%d/search", tcgApiCatalogCategoriesURL, category)
+	var out []int
+	resp, err := tcg.postJSON(link, filters, &out)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return out, resp.TotalItems, nil
+}
+
+// A sorting option or a filter value of the search manifest
+type SearchManifestItem struct {
+	Text  string `json:"text"`
+	Value string `json:"value"`
+}
+
+// A filterable attribute of the search manifest, with its allowed values,
+// which are empty for free text filters such as "ProductName"
+type SearchManifestFilter struct {
+	Name        string               `json:"name"`
+	DisplayName string               `json:"displayName"`
+	InputType   string               `json:"inputType"`
+	Items       []SearchManifestItem `json:"items"`
+}
+
+// The sorting options and filters accepted by SearchProducts for a category
+type SearchManifest struct {
+	Sorting []SearchManifestItem   `json:"sorting"`
+	Filters []SearchManifestFilter `json:"filters"`
+}
+
+// Retrieve the sorting options and filters that can be used to search the
+// products of a category
+func (tcg *Client) GetSearchManifest(category int) (*SearchManifest, error) {
+	link := fmt.Sprintf("%s/%d/search/manifest", tcgApiCatalogCategoriesURL, category)
+	out, _, err := getResults[SearchManifest](tcg, link)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("search manifest of category %d: %w", category, ErrNotFound)
+	}
+
+	return &out[0], nil
+}
@@ -0,0 +1,53 @@
+package tcgplayer
+
+// Retrieve details for any number of SKUs, splitting the ids in chunks of
+// MaxIdsInRequest, and passing each completed chunk to fn
+func (tcg *Client) GetAllSKUDetailsFunc(skuIds []int, fn func([]SKU) error) error {
+	return runBatches(tcg, skuIds, (*Client).GetSKUDetails, fn)
+}
+
+// Retrieve details for any number of SKUs, in the same order as the input ids.
+// On error, the results of the chunks completed so far are returned as well.
+func (tcg *Client) GetAllSKUDetails(skuIds []int) ([]SKU, error) {
+	var out []SKU
+	err := tcg.GetAllSKUDetailsFunc(skuIds, func(skus []SKU) error {
+		out = append(out, skus...)
+		return nil
+	})
+	sortByIds(out, skuIds, func(item SKU) int {
+		return item.SkuId
+	})
+	return out, err
+}
+
+// Retrieve the parent product id of each of the given SKUs, indexed by sku
+// id, so that any id missing from the map was not returned by the API.
+// On error, the results of the chunks completed so far are returned as well.
+func (tcg *Client) SKUsToProducts(skuIds []int) (map[int]int, error) {
+	out := make(map[int]int, len(skuIds))
+	err := tcg.GetAllSKUDetailsFunc(skuIds, func(skus []SKU) error {
+		for _, sku := range skus {
+			out[sku.SkuId] = sku.ProductId
+		}
+		return nil
+	})
+	return out, err
+}
+
+// Retrieve the ids of all the SKUs of each of the given products, indexed by
+// product id, so that any id missing from the map was not returned by the API.
+// On error, the results of the chunks completed so far are returned as well.
+func (tcg *Client) ProductsToSKUs(productIds []int) (map[int][]int, error) {
+	out := make(map[int][]int, len(productIds))
+	err := tcg.GetAllProductsDetailsFunc(productIds, true, func(products []Product) error {
+		for _, product := range products {
+			skuIds := make([]int, 0, len(product.Skus))
+			for _, sku := range product.Skus {
+				skuIds = append(skuIds, sku.SkuId)
+			}
+			out[product.ProductId] = skuIds
+		}
+		return nil
+	})
+	return out, err
+}
@@ -0,0 +1,40 @@
+package tcgplayer
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGetAllSKUDetailsChunks(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// Reply in reverse order, which the results should not follow
+		var skus []SKU
+		for _, field := range strings.Split(strings.TrimPrefix(r.URL.Path, "/catalog/skus/"), ",") {
+			id, _ := strconv.Atoi(field)
+			skus = append([]SKU{{SkuId: id, ProductId: id / 10}}, skus...)
+		}
+		if len(skus) > MaxIdsInRequest {
+			t.Errorf("requested %d ids at once", len(skus))
+		}
+		writeResults(w, len(skus), skus)
+	})
+
+	skuIds := sequentialIds(600)
+	skus, err := stub.client().GetAllSKUDetails(skuIds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skus) != len(skuIds) {
+		t.Fatalf("got %d skus, expected %d", len(skus), len(skuIds))
+	}
+	for i, sku := range skus {
+		if sku.SkuId != skuIds[i] {
+			t.Fatalf("sku %d has id %d, expected the input order", i, sku.SkuId)
+		}
+	}
+	if n := stub.requests.Load(); n != 3 {
+		t.Errorf("sent %d requests, expected 3", n)
+	}
+}
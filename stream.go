@@ -0,0 +1,139 @@
+package tcgplayer
+
+import (
+	"context"
+	"sync"
+)
+
+// Stream every SKU of a category, paging through its products one page at
+// a time, so that memory usage does not depend on the size of the category.
+// The SKU channel is closed once all pages are processed or ctx is cancelled;
+// the error channel then receives at most one error before being closed.
+func (tcg *Client) StreamSKUs(ctx context.Context, category int) (<-chan SKU, <-chan error) {
+	skus := make(chan SKU)
+	errs := make(chan error, 1)
+
+	client := tcg.withContext(ctx)
+
+	go func() {
+		defer close(errs)
+		defer close(skus)
+
+		total := 1
+		for offset := 0; offset < total; offset += MaxItemsInResponse {
+			products, resp, err := client.listProducts(productQuery{
+				category:    category,
+				includeSkus: true,
+				offset:      offset,
+			})
+			if err != nil {
+				errs <- err
+				return
+			}
+			total = resp.TotalItems
+
+			for _, product := range products {
+				for _, sku := range product.Skus {
+					sku.ProductId = product.ProductId
+					select {
+					case skus <- sku:
+					case <-ctx.Done():
+						errs <- ctx.Err()
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return skus, errs
+}
+
+// Stream the market prices of every product of a category, requesting the
+// prices of each group concurrently with the given number of workers, all
+// sharing the Client rate limiter. The price channel is closed once all
+// groups are processed or ctx is cancelled; the error channel then receives
+// at most one error, the first one encountered, before being closed.
+func (tcg *Client) StreamCategoryPrices(ctx context.Context, category int, workers int) (<-chan ProductPriceSet, <-chan error) {
+	prices := make(chan ProductPriceSet)
+	errs := make(chan error, 1)
+
+	if workers <= 0 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	client := tcg.withContext(ctx)
+
+	var once sync.Once
+	fail := func(err error) {
+		once.Do(func() {
+			errs <- err
+			cancel()
+		})
+	}
+
+	go func() {
+		defer cancel()
+		defer close(errs)
+		defer close(prices)
+
+		groups, err := client.GetAllCategoryGroups(category)
+		if err != nil {
+			fail(err)
+			return
+		}
+
+		groupIds := make(chan int)
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for groupId := range groupIds {
+					results, err := client.GetMarketPricesByGroup(groupId)
+					if err != nil {
+						fail(err)
+						return
+					}
+					for _, price := range results {
+						select {
+						case prices <- price:
+						case <-ctx.Done():
+							return
+						}
+					}
+				}
+			}()
+		}
+
+	loop:
+		for _, group := range groups {
+			select {
+			case groupIds <- group.GroupID:
+			case <-ctx.Done():
+				break loop
+			}
+		}
+		close(groupIds)
+		wg.Wait()
+
+		if ctx.Err() != nil {
+			fail(ctx.Err())
+		}
+	}()
+
+	return prices, errs
+}
+
+// Retrieve the market prices of every product of a category, requesting the
+// prices of each group concurrently with the given number of workers
+func (tcg *Client) SnapshotCategoryPrices(category int, workers int) ([]ProductPriceSet, error) {
+	prices, errs := tcg.StreamCategoryPrices(tcg.ctx, category, workers)
+
+	var out []ProductPriceSet
+	for price := range prices {
+		out = append(out, price)
+	}
+	return out, <-errs
+}
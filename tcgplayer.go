@@ -2,12 +2,15 @@ package tcgplayer
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"runtime/debug"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -17,31 +20,80 @@ import (
 	"golang.org/x/time/rate"
 )
 
+// Returned by single item lookups when the requested id yields no result
+var ErrNotFound = errors.New("not found")
+
 const (
 	MaxItemsInResponse = 100
 	MaxIdsInRequest    = 250
 )
 
+// How many times a failed request is retried by default
+const defaultRetryMax = 4
+
+// How long a failed token request is remembered before trying again
+const tokenFailureBackoff = 5 * time.Second
+
+// Identify requests of this package unless changed with WithUserAgent
+var defaultUserAgent = "go-tcgplayer/" + moduleVersion() + " (+https://" + modulePath + ")"
+
+const modulePath = "github.com/mtgban/go-tcgplayer"
+
+// Return the version of this module recorded in the build information of the
+// binary, or "devel" when unknown, as in tests or local checkouts
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath && dep.Version != "" {
+			return dep.Version
+		}
+	}
+	if info.Main.Path == modulePath && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "devel"
+}
+
+// How long before its expiration a token is replaced by default
+const defaultTokenRefreshLead = time.Hour
+
+// How much of an undecodable response is quoted in the error
+const maxQuotedBody = 512
+
 const (
 	tcgApiVersion = "v1.39.0"
 
-	tcgApiTokenURL = "https://api.tcgplayer.com/token"
+	tcgApiHost = "https://api.tcgplayer.com"
 
-	tcgApiCatalogCategoriesURL = "https://api.tcgplayer.com/" + tcgApiVersion + "/catalog/categories"
-	tcgApiCatalogProductsURL   = "https://api.tcgplayer.com/" + tcgApiVersion + "/catalog/products"
-	tcgApiCatalogGroupsURL     = "https://api.tcgplayer.com/" + tcgApiVersion + "/catalog/groups"
+	tcgApiTokenURL = tcgApiHost + "/token"
 
-	tcgApiPricingProductURL = "https://api.tcgplayer.com/" + tcgApiVersion + "/pricing/product"
-	tcgApiPricingSkuURL     = "https://api.tcgplayer.com/" + tcgApiVersion + "/pricing/sku"
+	tcgApiBaseURL = tcgApiHost + "/" + tcgApiVersion
+
+	tcgApiCatalogCategoriesURL = tcgApiBaseURL + "/catalog/categories"
+	tcgApiCatalogProductsURL   = tcgApiBaseURL + "/catalog/products"
+	tcgApiCatalogGroupsURL     = tcgApiBaseURL + "/catalog/groups"
+	tcgApiCatalogSkusURL       = tcgApiBaseURL + "/catalog/skus"
+
+	tcgApiPricingProductURL = tcgApiBaseURL + "/pricing/product"
+	tcgApiPricingSkuURL     = tcgApiBaseURL + "/pricing/sku"
+	tcgApiPricingGroupURL   = tcgApiBaseURL + "/pricing/group"
+
+	tcgApiBuylistSkuURL     = tcgApiBaseURL + "/pricing/buy/sku"
+	tcgApiBuylistProductURL = tcgApiBaseURL + "/pricing/buy/product"
 )
 
-// All active categories on the platform
+// Categories known to this package. The blank entries are ids without a
+// constant, keeping the others aligned with the API ids, and are excluded
+// from AllCategories and CategoryName.
 const (
 	CategoryMagic = iota + 1
 	CategoryYuGiOh
 	CategoryPokemon
 	CategoryAxisAllies
-	_
+	_ // 5, no constant
 	CategoryDDMiniatures
 	CategoryEpic
 	CategoryHeroclix
@@ -57,7 +109,7 @@ const (
 	CategoryDiceMasters
 	CategoryFutureCardBuddyFight
 	CategoryWeissSchwarz
-	_
+	_ // 21, no constant
 	CategoryTCGplayer
 	CategoryDragonBallZ
 	CategoryFinalFantasy
@@ -119,8 +171,8 @@ const (
 	CategoryDragonBallSuperFusionWorld
 	CategoryUnionArena
 	CategoryTCGplayerSupplies
-	_
-	_
+	_ // 83, no constant
+	_ // 84, no constant
 )
 
 // List of all possible product types
@@ -147,35 +199,254 @@ var ProductTypesSingles = []string{AllProductTypes[0]}
 // List of all product types containing Sealed Products
 var ProductTypesSealed = AllProductTypes[1:len(AllProductTypes)]
 
+// A Client is safe for concurrent use by multiple goroutines, and should be
+// reused rather than created for each call. Its settings never change after
+// NewClient or Clone, while the token, the metadata caches, the rate limiter,
+// and the request budget are guarded by their own locks, so that concurrent
+// calls share a single token request and a single rate limit. Slices and
+// maps returned by cached methods, such as GetCategoryMetadata, are shared
+// across callers and must not be modified.
 type Client struct {
 	client *retryablehttp.Client
+	auth   *authTransport
+
+	// Settings that can be customized via ClientOption
+	ctx       context.Context
+	timeout   time.Duration
+	baseURL   string
+	tokenURL  string
+	userAgent string
+	limiter   *rate.Limiter
+	logger    retryablehttp.Logger
+	tlsConfig *tls.Config
+
+	httpClient *http.Client
+	transport  http.RoundTripper
+	tokenStore TokenStore
+	observer   func(RequestInfo)
+
+	tokenRefreshLead time.Duration
+
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+
+	dedupeProducts bool
+	emptyRetries   int
+	failFast       bool
+
+	checkCredentials bool
+
+	batchConcurrency int
+
+	retryMax    int
+	retryPolicy func(*http.Response, error) (bool, error)
+
+	// Categories whose metadata is loaded at construction
+	warm []warmCategory
+
+	// Credentials and token, shared across clones
+	tokens *tokenCache
+
+	// Catalog metadata, shared across clones
+	cache *metadataCache
+
+	// Request counter, shared across clones unless a new budget is set
+	budget *requestBudget
+
+	// Rate limit headers of the latest response, shared across clones
+	rateLimits *rateLimitStatus
 }
 
-func NewClient(publicKey, privateKey string) *Client {
-	tcg := Client{}
-	tcg.client = retryablehttp.NewClient()
-	tcg.client.Logger = nil
-	tcg.client.HTTPClient.Transport = &authTransport{
-		parent:     tcg.client.HTTPClient.Transport,
-		publicKey:  publicKey,
-		privateKey: privateKey,
+func NewClient(publicKey, privateKey string, opts ...ClientOption) *Client {
+	// Keys copied from env files often carry trailing newlines
+	publicKey = strings.TrimSpace(publicKey)
+	privateKey = strings.TrimSpace(privateKey)
+
+	tcg := Client{
+		ctx: context.Background(),
 
 		// Set a relatively high rate to prevent unexpected limits later
 		limiter: rate.NewLimiter(80, 20),
 
-		mtx: sync.RWMutex{},
+		retryMax:  defaultRetryMax,
+		userAgent: defaultUserAgent,
+
+		tokenRefreshLead: defaultTokenRefreshLead,
+
+		tokens: &tokenCache{
+			publicKey:  publicKey,
+			privateKey: privateKey,
+		},
+		cache:      newMetadataCache(),
+		budget:     &requestBudget{},
+		rateLimits: &rateLimitStatus{},
 	}
+	for _, opt := range opts {
+		opt(&tcg)
+	}
+	tcg.setup()
+	tcg.warmCategories()
 	return &tcg
 }
 
-type authTransport struct {
-	parent     http.RoundTripper
+// Same as NewClient, but return an error if either key is blank, or, when
+// WithCredentialsCheck is set, if the keys cannot be used to get a token
+func NewClientValidated(publicKey, privateKey string, opts ...ClientOption) (*Client, error) {
+	if strings.TrimSpace(publicKey) == "" {
+		return nil, errors.New("missing public key")
+	}
+	if strings.TrimSpace(privateKey) == "" {
+		return nil, errors.New("missing private key")
+	}
+
+	tcg := NewClient(publicKey, privateKey, opts...)
+	if tcg.checkCredentials {
+		err := tcg.VerifyCredentials()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tcg, nil
+}
+
+// Make sure that the keys are valid by getting a token, unless a valid one
+// is already available, either in memory or from the token store
+func (tcg *Client) VerifyCredentials() error {
+	_, err := tcg.auth.getToken()
+	if err != nil {
+		return fmt.Errorf("unable to verify credentials: %w", err)
+	}
+	return nil
+}
+
+// Create a new Client with the same configuration, with any option applied
+// on top of it. The credentials, the token, and the metadata caches are
+// always shared with the original Client, so that no new authentication is
+// performed. The rate limiter is shared too, unless a new one is set with
+// WithRateLimit.
+func (tcg *Client) Clone(opts ...ClientOption) *Client {
+	clone := *tcg
+	clone.warm = nil
+	for _, opt := range opts {
+		opt(&clone)
+	}
+	clone.setup()
+	clone.warmCategories()
+	return &clone
+}
+
+// Return a shallow copy of the Client performing its requests with the given
+// context. Unlike Clone, the underlying http client and its connection pool
+// are reused, so it is cheap enough to be called for every batch or stream.
+func (tcg *Client) withContext(ctx context.Context) *Client {
+	client := *tcg
+	client.ctx = ctx
+	return &client
+}
+
+// Build the underlying http client according to the current settings
+func (tcg *Client) setup() {
+	tcg.client = retryablehttp.NewClient()
+	if tcg.httpClient != nil {
+		// Work on a copy, the original client is never modified
+		httpClient := *tcg.httpClient
+		if httpClient.Transport == nil {
+			httpClient.Transport = http.DefaultTransport
+		}
+		tcg.client.HTTPClient = &httpClient
+	}
+	if tcg.transport != nil {
+		tcg.client.HTTPClient.Transport = tcg.transport
+	}
+	// Same for any transport provided by the caller
+	if tcg.httpClient != nil || tcg.transport != nil {
+		if transport, ok := tcg.client.HTTPClient.Transport.(*http.Transport); ok {
+			tcg.client.HTTPClient.Transport = transport.Clone()
+		}
+	}
+	tcg.client.Logger = tcg.logger
+	tcg.client.RetryMax = tcg.retryMax
+	// The default backoff waits as long as requested by the Retry-After header
+	// of 429 responses, and the last response is kept once retries are over,
+	// so that throttling can be reported as a RateLimitError
+	tcg.client.Backoff = retryablehttp.DefaultBackoff
+	tcg.client.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	if tcg.observer != nil {
+		tcg.client.RequestLogHook = countRetries
+	}
+	tcg.client.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		// Retrying would only make the same request fail again
+		if errors.Is(err, ErrBudgetExceeded) {
+			return false, err
+		}
+		if tcg.retryPolicy != nil {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			return tcg.retryPolicy(resp, err)
+		}
+		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	}
+
+	transport, ok := tcg.client.HTTPClient.Transport.(*http.Transport)
+	if ok {
+		if tcg.tlsConfig != nil {
+			transport.TLSClientConfig = tcg.tlsConfig
+		}
+		if tcg.maxIdleConns > 0 {
+			transport.MaxIdleConns = tcg.maxIdleConns
+		}
+		if tcg.maxIdleConnsPerHost > 0 {
+			transport.MaxIdleConnsPerHost = tcg.maxIdleConnsPerHost
+		}
+	}
+
+	tokenURL := tcg.tokenURL
+	if tokenURL == "" {
+		tokenURL = tcg.resolveURL(tcgApiTokenURL)
+	}
+
+	tcg.auth = &authTransport{
+		parent:      tcg.client.HTTPClient.Transport,
+		tokenURL:    tokenURL,
+		userAgent:   tcg.userAgent,
+		limiter:     tcg.limiter,
+		budget:      tcg.budget,
+		logger:      tcg.logger,
+		store:       tcg.tokenStore,
+		refreshLead: tcg.tokenRefreshLead,
+		tokenCache:  tcg.tokens,
+	}
+	tcg.client.HTTPClient.Transport = tcg.auth
+}
+
+type tokenCache struct {
 	publicKey  string
 	privateKey string
 	token      string
 	expires    time.Time
-	limiter    *rate.Limiter
 	mtx        sync.RWMutex
+
+	// Last token request failure, used to avoid hammering the endpoint
+	lastErr     error
+	lastFailure time.Time
+
+	// Last token rejected by the API, never to be loaded again from a store
+	revoked string
+}
+
+type authTransport struct {
+	parent    http.RoundTripper
+	tokenURL  string
+	userAgent string
+	limiter   *rate.Limiter
+	budget    *requestBudget
+	logger    retryablehttp.Logger
+	store     TokenStore
+
+	refreshLead time.Duration
+
+	*tokenCache
 }
 
 func (t *authTransport) requestToken() (string, time.Time, error) {
@@ -184,7 +455,19 @@ func (t *authTransport) requestToken() (string, time.Time, error) {
 	params.Set("client_id", t.publicKey)
 	params.Set("client_secret", t.privateKey)
 
-	resp, err := cleanhttp.DefaultClient().PostForm(tcgApiTokenURL, params)
+	// Credentials are sent in the body, never log them
+	t.logf("[DEBUG] POST %s (client_id=%s client_secret=%s)", t.tokenURL, redacted, redacted)
+
+	// Use the same underlying transport to share any connection setting
+	client := cleanhttp.DefaultClient()
+	client.Transport = t.parent
+	req, err := http.NewRequest(http.MethodPost, t.tokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", t.userAgent)
+	resp, err := client.Do(req)
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -196,96 +479,436 @@ func (t *authTransport) requestToken() (string, time.Time, error) {
 	}
 
 	var response struct {
-		AccessToken string        `json:"access_token"`
-		ExpiresIn   time.Duration `json:"expires_in"`
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
 	}
 	err = json.Unmarshal(data, &response)
 	if err != nil {
 		return "", time.Time{}, err
 	}
+	if response.AccessToken == "" {
+		return "", time.Time{}, fmt.Errorf("token request failed (%s): %s", resp.Status, string(data))
+	}
 
-	expires := time.Now().Add(response.ExpiresIn * time.Second)
+	expires := time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
 	return response.AccessToken, expires, nil
 }
 
 func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	err := t.limiter.Wait(context.Background())
+	err := t.limiter.Wait(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	// Only count requests that are actually about to be sent
+	err = t.budget.take()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := t.getToken()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.send(req, token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The token may have been invalidated early on the server side, so force
+	// a refresh and try again, only once, if the request can be replayed
+	retry := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return resp, nil
+		}
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	// The replay is a request of its own, and counts as such
+	err = t.limiter.Wait(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	err = t.budget.take()
 	if err != nil {
 		return nil, err
 	}
 
+	t.invalidate(token)
+	token, err = t.getToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return t.send(retry, token)
+}
+
+func (t *authTransport) send(req *http.Request, token string) (*http.Response, error) {
+	t.logf("[DEBUG] %s %s (Authorization: Bearer %s)", req.Method, redactURL(req.URL), redacted)
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.parent.RoundTrip(req)
+}
+
+func (t *authTransport) logf(format string, args ...interface{}) {
+	if t.logger != nil {
+		t.logger.Printf(format, args...)
+	}
+}
+
+const redacted = "[REDACTED]"
+
+// Query parameters that must never be logged
+var sensitiveParams = []string{"client_id", "client_secret", "access_token"}
+
+// Return the URL as string, with any sensitive query parameter redacted
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	changed := false
+	for _, param := range sensitiveParams {
+		if query.Has(param) {
+			query.Set(param, redacted)
+			changed = true
+		}
+	}
+	if !changed {
+		return u.String()
+	}
+
+	clean := *u
+	clean.RawQuery = query.Encode()
+	return clean.String()
+}
+
+// Drop the given token, unless it was already replaced by another routine
+func (t *authTransport) invalidate(token string) {
+	t.mtx.Lock()
+	if t.token == token {
+		t.token = ""
+		t.revoked = token
+	}
+	t.mtx.Unlock()
+}
+
+// Whether a token expiring at the given time should be replaced
+func (t *authTransport) expiring(expires time.Time) bool {
+	return time.Now().After(expires.Add(-t.refreshLead))
+}
+
+// Use the token of the store, if any, unless it is about to expire or was
+// rejected by the API. Must be called with the token lock held.
+func (t *authTransport) loadStoredToken() bool {
+	if t.store == nil {
+		return false
+	}
+	token, expires, err := t.store.Load()
+	if err != nil {
+		t.logf("[WARN] unable to load the stored token: %s", err)
+		return false
+	}
+	if token == "" || token == t.revoked || t.expiring(expires) {
+		return false
+	}
+	t.token, t.expires = token, expires
+	return true
+}
+
+// Save the current token to the store, if any, logging any failure, as the
+// token is still usable. Must be called with the token lock held, so that
+// saves never overlap.
+func (t *authTransport) saveToken() {
+	if t.store == nil {
+		return
+	}
+	err := t.store.Save(t.token, t.expires)
+	if err != nil {
+		t.logf("[WARN] unable to save the token: %s", err)
+	}
+}
+
+// Return the current token, generating a new one if needed
+func (t *authTransport) getToken() (string, error) {
 	if t.publicKey == "" || t.privateKey == "" {
-		return nil, fmt.Errorf("missing public or private key")
+		return "", fmt.Errorf("missing public or private key")
 	}
 
+	var err error
+
 	// Retrieve the static values
 	t.mtx.RLock()
 	token := t.token
 	expires := t.expires
 	t.mtx.RUnlock()
 
-	// If there is a token, make sure it's still valid
-	if token != "" || time.Now().After(expires.Add(-1*time.Hour)) {
-		// If not valid, ask for generating a new one
-		t.mtx.Lock()
-		token = ""
-		t.mtx.Unlock()
-	}
-
-	// Generate a new token
-	if token == "" {
+	// Generate a new token if missing, or about to expire
+	if token == "" || t.expiring(expires) {
 		t.mtx.Lock()
 		// Only perform this action once, for the routine that got the mutex first
 		// The others will just use the updated token immediately after
-		if token == t.token {
-			t.token, t.expires, err = t.requestToken()
+		if token == t.token && !t.loadStoredToken() {
+			// If the token endpoint failed very recently, return the same
+			// error instead of letting every routine retry at the same time
+			if !t.lastFailure.IsZero() && time.Since(t.lastFailure) < tokenFailureBackoff {
+				err = t.lastErr
+			} else {
+				t.token, t.expires, err = t.requestToken()
+				if err != nil {
+					t.lastErr = err
+					t.lastFailure = time.Now()
+				} else {
+					t.saveToken()
+				}
+			}
 		}
 		token = t.token
 		t.mtx.Unlock()
 		// If anything fails
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 	}
 
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
-	return t.parent.RoundTrip(req)
+	return token, nil
 }
 
 type BaseResponse struct {
-	TotalItems int             `json:"totalItems"`
-	Success    bool            `json:"success"`
-	Errors     []string        `json:"errors"`
-	Results    json.RawMessage `json:"results"`
+	TotalItems int      `json:"totalItems"`
+	Success    bool     `json:"success"`
+	Errors     []string `json:"errors"`
+	// Empty when the results were decoded directly, as done by GetInto and
+	// the typed methods
+	Results json.RawMessage `json:"results"`
+
+	// Headers of the HTTP response, such as rate limit counters or request
+	// ids to quote in support tickets
+	Header http.Header `json:"-"`
 }
 
 // Perform an authenticated GET request and partially parse the response
 func (tcg *Client) GetRequest(link string) (*BaseResponse, error) {
-	resp, err := tcg.client.Get(link)
+	return tcg.doRequest(http.MethodGet, link, "", nil, nil)
+}
+
+// Perform an authenticated POST request with the given body encoded as JSON,
+// and partially parse the response
+func (tcg *Client) PostRequest(link string, body interface{}) (*BaseResponse, error) {
+	return tcg.postJSON(link, body, nil)
+}
+
+// Perform an authenticated POST request with a form-encoded body, and
+// partially parse the response
+func (tcg *Client) PostFormRequest(link string, values url.Values) (*BaseResponse, error) {
+	return tcg.doRequest(http.MethodPost, link, "application/x-www-form-urlencoded", []byte(values.Encode()), nil)
+}
+
+// Perform an authenticated POST request with a JSON body, decoding the
+// results into the given value
+func (tcg *Client) postJSON(link string, body interface{}, results interface{}) (*BaseResponse, error) {
+	data, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	return tcg.doRequest(http.MethodPost, link, "application/json", data, results)
+}
 
-	data, err := io.ReadAll(resp.Body)
+// Point a TCGplayer API URL to the host set with WithBaseURL, if any
+func (tcg *Client) resolveURL(link string) string {
+	if tcg.baseURL == "" || !strings.HasPrefix(link, tcgApiHost) {
+		return link
+	}
+	return tcg.baseURL + strings.TrimPrefix(link, tcgApiHost)
+}
+
+// Perform an authenticated request and partially parse the response.
+// If results is set, the results are decoded into it while reading the
+// response, without buffering them, otherwise they are kept as raw JSON.
+func (tcg *Client) doRequest(method, link, contentType string, body []byte, results interface{}) (*BaseResponse, error) {
+	link = tcg.resolveURL(link)
+	if tcg.observer == nil {
+		return tcg.sendRequest(method, link, contentType, body, results, nil)
+	}
+
+	info := RequestInfo{
+		Method: method,
+		URL:    link,
+	}
+	start := time.Now()
+	resp, err := tcg.sendRequest(method, link, contentType, body, results, &info)
+	info.Duration = time.Since(start)
+	info.Err = err
+	tcg.observer(info)
+
+	return resp, err
+}
+
+// Perform a request, filling info with its outcome if set
+func (tcg *Client) sendRequest(method, link, contentType string, body []byte, results interface{}, info *RequestInfo) (*BaseResponse, error) {
+	ctx := tcg.ctx
+	if info != nil {
+		ctx = context.WithValue(ctx, retriesKey{}, &info.Retries)
+	}
+	if tcg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, tcg.timeout)
+		defer cancel()
+	}
+
+	var reqBody interface{}
+	if body != nil {
+		reqBody = body
+	}
+	req, err := retryablehttp.NewRequestWithContext(ctx, method, link, reqBody)
 	if err != nil {
 		return nil, err
 	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := tcg.client.Do(req)
+	if err != nil {
+		if resp != nil {
+			if info != nil {
+				info.HTTPStatus = resp.StatusCode
+			}
+			resp.Body.Close()
+		}
+		return nil, err
+	}
+	defer func() {
+		// Drain what was not decoded, so that the connection can be reused
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	tcg.rateLimits.update(resp.Header)
+
+	reader := &countingReader{r: resp.Body}
+	if info != nil {
+		info.HTTPStatus = resp.StatusCode
+		defer func() {
+			info.Bytes = reader.n
+		}()
+	}
+
+	// Still throttled after all the retries
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, newRateLimitError(resp, link)
+	}
 
+	// The results of an unsuccessful response are only decoded once it is
+	// known that they can be used, so that results is never partially set
+	success := resp.StatusCode/200 == 1
+	var raw json.RawMessage
 	var response BaseResponse
-	err = json.Unmarshal(data, &response)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %s", err.Error(), string(data))
+	envelope := struct {
+		TotalItems int         `json:"totalItems"`
+		Success    bool        `json:"success"`
+		Errors     []string    `json:"errors"`
+		Results    interface{} `json:"results"`
+	}{
+		Results: results,
 	}
+	if !success {
+		envelope.Results = &raw
+	} else if results == nil {
+		envelope.Results = &response.Results
+	}
+	quoted := &prefixBuffer{max: maxQuotedBody}
+	err = json.NewDecoder(io.TeeReader(reader, quoted)).Decode(&envelope)
+	response.TotalItems = envelope.TotalItems
+	response.Success = envelope.Success
+	response.Errors = envelope.Errors
+
 	// Return error details only if the request fully failed
 	// Otherwise return as much as possible to the callee
-	if resp.StatusCode/200 != 1 && len(response.Errors) > 0 {
-		return nil, fmt.Errorf(strings.Join(response.Errors, " "))
+	// Server errors are only returned once retries are over, never use them
+	failed := !success && (len(response.Errors) > 0 || err != nil)
+	if failed || resp.StatusCode >= 500 {
+		if len(response.Errors) == 0 {
+			response.Errors = []string{resp.Status}
+		}
+		return nil, &APIError{
+			HTTPStatus: resp.StatusCode,
+			Errors:     response.Errors,
+			URL:        link,
+		}
 	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode response of %s: %w: %s", link, err, quoted.data)
+	}
+	if !success {
+		if results == nil {
+			response.Results = raw
+		} else if len(raw) > 0 {
+			err = json.Unmarshal(raw, results)
+			if err != nil {
+				return nil, fmt.Errorf("unable to decode response of %s: %w: %s", link, err, quoted.data)
+			}
+		}
+	}
+	response.Header = resp.Header
 
 	return &response, nil
 }
 
+// Keep the first bytes written to it, up to max, discarding the rest
+type prefixBuffer struct {
+	data []byte
+	max  int
+}
+
+func (b *prefixBuffer) Write(p []byte) (int, error) {
+	n := b.max - len(b.data)
+	if n > len(p) {
+		n = len(p)
+	}
+	if n > 0 {
+		b.data = append(b.data, p[:n]...)
+	}
+	return len(p), nil
+}
+
+// Perform an authenticated GET request and decode its results as a slice
+func getResults[T any](tcg *Client, link string) ([]T, *BaseResponse, error) {
+	var out []T
+	resp, err := tcg.doRequest(http.MethodGet, link, "", nil, &out)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return out, resp, nil
+}
+
+// Perform an authenticated GET request on an API path, such as
+// "/catalog/categories", and decode its results into target.
+// This is the building block for any endpoint not covered by this package.
+func (tcg *Client) GetInto(path string, params url.Values, target interface{}) error {
+	_, err := tcg.GetIntoWithResponse(path, params, target)
+	return err
+}
+
+// Same as GetInto, also returning the full response metadata, including the
+// response headers
+func (tcg *Client) GetIntoWithResponse(path string, params url.Values, target interface{}) (*BaseResponse, error) {
+	u, err := url.Parse(tcgApiBaseURL + "/" + strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if params != nil {
+		u.RawQuery = params.Encode()
+	}
+
+	return tcg.doRequest(http.MethodGet, u.String(), "", nil, target)
+}
+
 func (tcg *Client) TotalProducts(category int, productTypes []string) (int, error) {
 	return tcg.queryTotal(tcgApiCatalogProductsURL, category, productTypes)
 }
@@ -298,6 +921,32 @@ func (tcg *Client) TotalCategories(category int) (int, error) {
 	return tcg.queryTotal(tcgApiCatalogCategoriesURL, category, nil)
 }
 
+// Estimate how many requests are needed to dump a category like tcgdumper
+// does: category details, and every page of groups and products, the first
+// page of each being always requested as it reports the totals.
+// Skus are embedded in the products pages, so includeSkus does not add any
+// request, but makes each page heavier. Computing the estimate costs two
+// requests itself.
+func (tcg *Client) EstimateRequests(category int, productTypes []string, includeSkus bool) (int, error) {
+	totalGroups, err := tcg.TotalGroups(category)
+	if err != nil {
+		return 0, err
+	}
+	totalProducts, err := tcg.TotalProducts(category, productTypes)
+	if err != nil {
+		return 0, err
+	}
+
+	pages := func(total int) int {
+		if total == 0 {
+			return 1
+		}
+		return (total + MaxItemsInResponse - 1) / MaxItemsInResponse
+	}
+
+	return 1 + pages(totalGroups) + pages(totalProducts), nil
+}
+
 // Retrieve how many items a full call will be
 func (tcg *Client) queryTotal(link string, category int, productTypes []string) (int, error) {
 	u, err := url.Parse(link)
@@ -323,17 +972,59 @@ type Printing struct {
 	PrintingId   int    `json:"printingId"`
 	Name         string `json:"name"`
 	DisplayOrder int    `json:"displayOrder"`
-	ModifiedOn   string `json:"modifiedOn`
+	ModifiedOn   string `json:"modifiedOn"`
 }
 
 func (tcg *Client) ListCategoryPrintings(category int) ([]Printing, error) {
-	resp, err := tcg.GetRequest(fmt.Sprintf("%s/%d/printings", tcgApiCatalogCategoriesURL, category))
+	out, _, err := getResults[Printing](tcg, fmt.Sprintf("%s/%d/printings", tcgApiCatalogCategoriesURL, category))
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+type Condition struct {
+	ConditionId  int    `json:"conditionId"`
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbreviation"`
+	DisplayOrder int    `json:"displayOrder"`
+}
+
+func (tcg *Client) ListCategoryConditions(category int) ([]Condition, error) {
+	out, _, err := getResults[Condition](tcg, fmt.Sprintf("%s/%d/conditions", tcgApiCatalogCategoriesURL, category))
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+type Language struct {
+	LanguageId   int    `json:"languageId"`
+	Name         string `json:"name"`
+	Abbreviation string `json:"abbr"`
+}
+
+func (tcg *Client) ListCategoryLanguages(category int) ([]Language, error) {
+	out, _, err := getResults[Language](tcg, fmt.Sprintf("%s/%d/languages", tcgApiCatalogCategoriesURL, category))
 	if err != nil {
 		return nil, err
 	}
 
-	var out []Printing
-	err = json.Unmarshal(resp.Results, &out)
+	return out, nil
+}
+
+// A rarity of a category, with DisplayText being its name, such as "Mythic",
+// and DbValue its short code, such as "M"
+type Rarity struct {
+	RarityId    int    `json:"rarityId"`
+	DisplayText string `json:"displayText"`
+	DbValue     string `json:"dbValue"`
+}
+
+func (tcg *Client) ListCategoryRarities(category int) ([]Rarity, error) {
+	out, _, err := getResults[Rarity](tcg, fmt.Sprintf("%s/%d/rarities", tcgApiCatalogCategoriesURL, category))
 	if err != nil {
 		return nil, err
 	}
@@ -353,16 +1044,35 @@ type Product struct {
 	// Only available for catalog API calls
 	Skus []SKU `json:"skus,omitempty"`
 	// Only available for catalog API calls
-	ExtendedData []struct {
-		Name        string `json:"name"`
-		DisplayName string `json:"displayName"`
-		Value       string `json:"value"`
-	} `json:"extendedData,omitempty"`
+	ExtendedData []ExtendedData `json:"extendedData,omitempty"`
+}
+
+type ExtendedData struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Value       string `json:"value"`
+}
+
+// Return the value of the extended field with the given name (such as
+// "Number" or "Rarity"), if present
+func (p Product) ExtendedValue(name string) (string, bool) {
+	for _, data := range p.ExtendedData {
+		if data.Name == name {
+			return data.Value, true
+		}
+	}
+	return "", false
 }
 
 func (tcg *Client) GetProductsDetails(productIds []int, includeSkus bool) ([]Product, error) {
+	out, _, err := tcg.GetProductsDetailsWithResponse(productIds, includeSkus)
+	return out, err
+}
+
+// Same as GetProductsDetails, also returning the full response metadata
+func (tcg *Client) GetProductsDetailsWithResponse(productIds []int, includeSkus bool) ([]Product, *BaseResponse, error) {
 	if len(productIds) > MaxIdsInRequest {
-		return nil, errors.New("too many ids in request")
+		return nil, nil, errors.New("too many ids in request")
 	}
 
 	ids := ints2strings(productIds)
@@ -370,7 +1080,7 @@ func (tcg *Client) GetProductsDetails(productIds []int, includeSkus bool) ([]Pro
 
 	u, err := url.Parse(link)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	v := url.Values{}
@@ -381,51 +1091,168 @@ func (tcg *Client) GetProductsDetails(productIds []int, includeSkus bool) ([]Pro
 
 	u.RawQuery = v.Encode()
 
-	resp, err := tcg.GetRequest(u.String())
+	return getResults[Product](tcg, u.String())
+}
+
+// Retrieve details for a single product
+func (tcg *Client) GetProduct(productId int, includeSkus bool) (*Product, error) {
+	products, err := tcg.GetProductsDetails([]int{productId}, includeSkus)
 	if err != nil {
 		return nil, err
 	}
+	if len(products) == 0 {
+		return nil, fmt.Errorf("product %d: %w", productId, ErrNotFound)
+	}
+	return &products[0], nil
+}
 
-	var out []Product
-	err = json.Unmarshal(resp.Results, &out)
+func (tcg *Client) ListAllProducts(category int, productTypes []string, includeSkus bool, offset int) ([]Product, error) {
+	out, _, err := tcg.ListAllProductsWithResponse(category, productTypes, includeSkus, offset)
+	return out, err
+}
+
+// Same as ListAllProducts, also returning the total number of products, so
+// that the first page can be used to know how many pages are left
+func (tcg *Client) ListAllProductsPage(category int, productTypes []string, includeSkus bool, offset int) ([]Product, int, error) {
+	out, resp, err := tcg.ListAllProductsWithResponse(category, productTypes, includeSkus, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return out, resp.TotalItems, nil
+}
+
+// Same as ListAllProducts, also returning the full response metadata
+func (tcg *Client) ListAllProductsWithResponse(category int, productTypes []string, includeSkus bool, offset int) ([]Product, *BaseResponse, error) {
+	return tcg.listProducts(productQuery{
+		category:     category,
+		productTypes: productTypes,
+		includeSkus:  includeSkus,
+		extended:     true,
+		offset:       offset,
+	})
+}
+
+// Retrieve the ids of all the products in a category, with extended fields
+// and skus disabled to keep the payload as small as possible
+func (tcg *Client) ListAllProductIDs(category int, productTypes []string) ([]int, error) {
+	total, err := tcg.TotalProducts(category, productTypes)
 	if err != nil {
 		return nil, err
 	}
 
+	out := make([]int, 0, total)
+	for offset := 0; offset < total; offset += MaxItemsInResponse {
+		products, _, err := tcg.listProducts(productQuery{
+			category:     category,
+			productTypes: productTypes,
+			offset:       offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range products {
+			out = append(out, product.ProductId)
+		}
+	}
+
 	return out, nil
 }
 
-func (tcg *Client) ListAllProducts(category int, productTypes []string, includeSkus bool, offset int) ([]Product, error) {
+// Same as ListAllProducts, but only for the products of a single group,
+// also returning the total number of products of the group
+func (tcg *Client) ListAllGroupProductsPage(groupId int, productTypes []string, includeSkus bool, offset int) ([]Product, int, error) {
+	out, resp, err := tcg.listProducts(productQuery{
+		groupId:      groupId,
+		productTypes: productTypes,
+		includeSkus:  includeSkus,
+		extended:     true,
+		offset:       offset,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return out, resp.TotalItems, nil
+}
+
+// Retrieve all the products of a group, such as a newly released set, without
+// paging through the whole category
+func (tcg *Client) GetAllGroupProducts(groupId int, productTypes []string, includeSkus bool) ([]Product, error) {
+	var out []Product
+	total := 1
+	for offset := 0; offset < total; offset += MaxItemsInResponse {
+		products, count, err := tcg.ListAllGroupProductsPage(groupId, productTypes, includeSkus, offset)
+		if err != nil {
+			return nil, err
+		}
+		total = count
+		out = append(out, products...)
+	}
+
+	return out, nil
+}
+
+type productQuery struct {
+	category     int
+	groupId      int
+	productTypes []string
+	includeSkus  bool
+	extended     bool
+	offset       int
+}
+
+func (tcg *Client) listProducts(query productQuery) ([]Product, *BaseResponse, error) {
 	u, err := url.Parse(tcgApiCatalogProductsURL)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	v := url.Values{}
-	v.Set("getExtendedFields", "true")
-	v.Set("categoryId", fmt.Sprint(category))
-	if productTypes != nil {
-		v.Set("productTypes", strings.Join(productTypes, ","))
+	if query.extended {
+		v.Set("getExtendedFields", "true")
 	}
-	if includeSkus {
+	if query.category != 0 {
+		v.Set("categoryId", fmt.Sprint(query.category))
+	}
+	if query.groupId != 0 {
+		v.Set("groupId", fmt.Sprint(query.groupId))
+	}
+	if query.productTypes != nil {
+		v.Set("productTypes", strings.Join(query.productTypes, ","))
+	}
+	if query.includeSkus {
 		v.Set("includeSkus", "true")
 	}
-	v.Set("offset", fmt.Sprint(offset))
+	v.Set("offset", fmt.Sprint(query.offset))
 	v.Set("limit", fmt.Sprint(MaxItemsInResponse))
 	u.RawQuery = v.Encode()
 
-	resp, err := tcg.GetRequest(u.String())
+	out, resp, err := getResults[Product](tcg, u.String())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	var out []Product
-	err = json.Unmarshal(resp.Results, &out)
-	if err != nil {
-		return nil, err
+	if tcg.dedupeProducts {
+		out = tcg.dedupe(out)
 	}
 
-	return out, nil
+	return out, resp, nil
+}
+
+// Drop any product appearing more than once, keeping the first occurrence
+func (tcg *Client) dedupe(products []Product) []Product {
+	seen := map[int]bool{}
+	out := products[:0]
+	for _, product := range products {
+		if seen[product.ProductId] {
+			if tcg.logger != nil {
+				tcg.logger.Printf("[WARN] dropping duplicate product %d", product.ProductId)
+			}
+			continue
+		}
+		seen[product.ProductId] = true
+		out = append(out, product)
+	}
+	return out
 }
 
 type SKU struct {
@@ -438,13 +1265,24 @@ type SKU struct {
 
 func (tcg *Client) ListProductSKUs(productId int) ([]SKU, error) {
 	link := fmt.Sprintf("%s/product/%d/skus", tcgApiCatalogProductsURL, productId)
-	resp, err := tcg.GetRequest(link)
+	out, _, err := getResults[SKU](tcg, link)
 	if err != nil {
 		return nil, err
 	}
 
-	var out []SKU
-	err = json.Unmarshal(resp.Results, &out)
+	return out, nil
+}
+
+// Retrieve details for a list of SKUs
+func (tcg *Client) GetSKUDetails(skuIds []int) ([]SKU, error) {
+	if len(skuIds) > MaxIdsInRequest {
+		return nil, errors.New("too many ids in request")
+	}
+
+	ids := ints2strings(skuIds)
+	link := tcgApiCatalogSkusURL + "/" + strings.Join(ids, ",")
+
+	out, _, err := getResults[SKU](tcg, link)
 	if err != nil {
 		return nil, err
 	}
@@ -462,10 +1300,54 @@ type Group struct {
 	CategoryID   int    `json:"categoryId"`
 }
 
+func (tcg *Client) GetGroupsDetails(groupIds []int) ([]Group, error) {
+	if len(groupIds) > MaxIdsInRequest {
+		return nil, errors.New("too many ids in request")
+	}
+
+	ids := ints2strings(groupIds)
+	link := tcgApiCatalogGroupsURL + "/" + strings.Join(ids, ",")
+
+	out, _, err := getResults[Group](tcg, link)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Retrieve details for a single group
+func (tcg *Client) GetGroup(groupId int) (*Group, error) {
+	groups, err := tcg.GetGroupsDetails([]int{groupId})
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("group %d: %w", groupId, ErrNotFound)
+	}
+	return &groups[0], nil
+}
+
 func (tcg *Client) ListAllCategoryGroups(category, offset int) ([]Group, error) {
+	out, _, err := tcg.ListAllCategoryGroupsWithResponse(category, offset)
+	return out, err
+}
+
+// Same as ListAllCategoryGroups, also returning the total number of groups,
+// so that the first page can be used to know how many pages are left
+func (tcg *Client) ListAllCategoryGroupsPage(category, offset int) ([]Group, int, error) {
+	out, resp, err := tcg.ListAllCategoryGroupsWithResponse(category, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return out, resp.TotalItems, nil
+}
+
+// Same as ListAllCategoryGroups, also returning the full response metadata
+func (tcg *Client) ListAllCategoryGroupsWithResponse(category, offset int) ([]Group, *BaseResponse, error) {
 	u, err := url.Parse(tcgApiCatalogGroupsURL)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	v := url.Values{}
 	v.Set("categoryId", fmt.Sprint(category))
@@ -473,15 +1355,23 @@ func (tcg *Client) ListAllCategoryGroups(category, offset int) ([]Group, error)
 	v.Set("limit", fmt.Sprint(MaxItemsInResponse))
 	u.RawQuery = v.Encode()
 
-	resp, err := tcg.GetRequest(u.String())
+	return getResults[Group](tcg, u.String())
+}
+
+// Retrieve all the groups of a category
+func (tcg *Client) GetAllCategoryGroups(category int) ([]Group, error) {
+	total, err := tcg.TotalGroups(category)
 	if err != nil {
 		return nil, err
 	}
 
-	var out []Group
-	err = json.Unmarshal(resp.Results, &out)
-	if err != nil {
-		return nil, err
+	out := make([]Group, 0, total)
+	for offset := 0; offset < total; offset += MaxItemsInResponse {
+		groups, err := tcg.ListAllCategoryGroups(category, offset)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, groups...)
 	}
 
 	return out, nil
@@ -508,20 +1398,76 @@ func (tcg *Client) GetCategoriesDetails(categoryIds []int) ([]Category, error) {
 	ids := ints2strings(categoryIds)
 	link := tcgApiCatalogCategoriesURL + "/" + strings.Join(ids, ",")
 
-	resp, err := tcg.GetRequest(link)
+	out, _, err := getResults[Category](tcg, link)
 	if err != nil {
 		return nil, err
 	}
 
-	var out []Category
-	err = json.Unmarshal(resp.Results, &out)
+	return out, nil
+}
+
+// Retrieve details for a single category
+func (tcg *Client) GetCategory(categoryId int) (*Category, error) {
+	categories, err := tcg.GetCategoriesDetails([]int{categoryId})
 	if err != nil {
 		return nil, err
 	}
+	if len(categories) == 0 {
+		return nil, fmt.Errorf("category %d: %w", categoryId, ErrNotFound)
+	}
+	return &categories[0], nil
+}
+
+func (tcg *Client) ListAllCategories(offset int) ([]Category, error) {
+	out, _, err := tcg.listCategories(offset, "", false)
+	return out, err
+}
+
+// Retrieve every category available on the platform, sorted by id, including
+// any category not yet listed in the Category constants
+func (tcg *Client) GetAllCategories() ([]Category, error) {
+	var out []Category
+	total := 1
+	for offset := 0; offset < total; offset += MaxItemsInResponse {
+		categories, resp, err := tcg.listCategories(offset, "", false)
+		if err != nil {
+			return nil, err
+		}
+		total = resp.TotalItems
+		out = append(out, categories...)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CategoryID < out[j].CategoryID
+	})
 
 	return out, nil
 }
 
+// Same as ListAllCategories, but with the most recently modified categories
+// first, useful to detect any change in the categories metadata
+func (tcg *Client) ListAllCategoriesByModified(offset int) ([]Category, error) {
+	out, _, err := tcg.listCategories(offset, "modifiedOn", true)
+	return out, err
+}
+
+func (tcg *Client) listCategories(offset int, sortOrder string, sortDesc bool) ([]Category, *BaseResponse, error) {
+	u, err := url.Parse(tcgApiCatalogCategoriesURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	v := url.Values{}
+	if sortOrder != "" {
+		v.Set("sortOrder", sortOrder)
+		v.Set("sortDesc", fmt.Sprint(sortDesc))
+	}
+	v.Set("offset", fmt.Sprint(offset))
+	v.Set("limit", fmt.Sprint(MaxItemsInResponse))
+	u.RawQuery = v.Encode()
+
+	return getResults[Category](tcg, u.String())
+}
+
 func ints2strings(ids []int) []string {
 	out := make([]string, 0, len(ids))
 	for i := range ids {
@@ -530,64 +1476,127 @@ func ints2strings(ids []int) []string {
 	return out
 }
 
+// Market prices of a product for a given subtype. The pricing responses,
+// for products as well as for SKUs, carry no listing or seller counts.
+// Prices are nil when not available, use the accessors to read them as zero.
 type ProductPriceSet struct {
-	ProductId      int     `json:"productId"`
-	LowPrice       float64 `json:"lowPrice"`
-	MarketPrice    float64 `json:"marketPrice"`
-	MidPrice       float64 `json:"midPrice"`
-	DirectLowPrice float64 `json:"directLowPrice"`
-	SubTypeName    string  `json:"subTypeName"`
+	ProductId      int      `json:"productId"`
+	LowPrice       *float64 `json:"lowPrice,omitempty"`
+	MarketPrice    *float64 `json:"marketPrice,omitempty"`
+	MidPrice       *float64 `json:"midPrice,omitempty"`
+	HighPrice      *float64 `json:"highPrice,omitempty"`
+	DirectLowPrice *float64 `json:"directLowPrice,omitempty"`
+	SubTypeName    string   `json:"subTypeName"`
 }
 
 func (tcg *Client) GetMarketPricesByProducts(productIds []int) ([]ProductPriceSet, error) {
+	out, _, err := tcg.GetMarketPricesByProductsWithResponse(productIds)
+	return out, err
+}
+
+// Same as GetMarketPricesByProducts, also returning the full response metadata
+func (tcg *Client) GetMarketPricesByProductsWithResponse(productIds []int) ([]ProductPriceSet, *BaseResponse, error) {
 	if len(productIds) > MaxIdsInRequest {
-		return nil, errors.New("too many ids in request")
+		return nil, nil, errors.New("too many ids in request")
 	}
 
 	ids := ints2strings(productIds)
 	link := tcgApiPricingProductURL + "/" + strings.Join(ids, ",")
 
-	resp, err := tcg.GetRequest(link)
+	return getPrices[ProductPriceSet](tcg, link, len(productIds) > 0)
+}
+
+// Retrieve market prices of all the products of a group, one price set per
+// product subtype. If the response holds fewer items than its total, the
+// remaining ones are requested page by page from the following offsets.
+func (tcg *Client) GetMarketPricesByGroup(groupId int) ([]ProductPriceSet, error) {
+	link := fmt.Sprintf("%s/%d", tcgApiPricingGroupURL, groupId)
+	out, resp, err := getPrices[ProductPriceSet](tcg, link, true)
 	if err != nil {
 		return nil, err
 	}
 
-	var out []ProductPriceSet
-	err = json.Unmarshal(resp.Results, &out)
-	if err != nil {
-		return nil, err
+	for len(out) < resp.TotalItems {
+		u, err := url.Parse(link)
+		if err != nil {
+			return nil, err
+		}
+		v := url.Values{}
+		v.Set("offset", fmt.Sprint(len(out)))
+		u.RawQuery = v.Encode()
+
+		prices, _, err := getResults[ProductPriceSet](tcg, u.String())
+		if err != nil {
+			return nil, err
+		}
+		// Nothing more to retrieve, even if the total said otherwise
+		if len(prices) == 0 {
+			break
+		}
+		out = append(out, prices...)
 	}
 
 	return out, nil
 }
 
+// Market prices of a SKU, nil when not available as for ProductPriceSet
 type SKUPriceSet struct {
-	SkuId              int     `json:"skuId"`
-	LowPrice           float64 `json:"lowPrice"`
-	LowestShipping     float64 `json:"lowestShipping"`
-	LowestListingPrice float64 `json:"lowestListingPrice"`
-	MarketPrice        float64 `json:"marketPrice"`
-	DirectLowPrice     float64 `json:"directLowPrice"`
+	SkuId              int      `json:"skuId"`
+	LowPrice           *float64 `json:"lowPrice,omitempty"`
+	LowestShipping     *float64 `json:"lowestShipping,omitempty"`
+	LowestListingPrice *float64 `json:"lowestListingPrice,omitempty"`
+	MarketPrice        *float64 `json:"marketPrice,omitempty"`
+	DirectLowPrice     *float64 `json:"directLowPrice,omitempty"`
 }
 
 func (tcg *Client) GetMarketPricesBySKUs(skuIds []int) ([]SKUPriceSet, error) {
+	out, _, err := tcg.GetMarketPricesBySKUsWithResponse(skuIds)
+	return out, err
+}
+
+// Same as GetMarketPricesBySKUs, also returning the full response metadata
+func (tcg *Client) GetMarketPricesBySKUsWithResponse(skuIds []int) ([]SKUPriceSet, *BaseResponse, error) {
 	if len(skuIds) > MaxIdsInRequest {
-		return nil, errors.New("too many ids in request")
+		return nil, nil, errors.New("too many ids in request")
 	}
 
 	ids := ints2strings(skuIds)
 	link := tcgApiPricingSkuURL + "/" + strings.Join(ids, ",")
 
-	resp, err := tcg.GetRequest(link)
-	if err != nil {
-		return nil, err
+	return getPrices[SKUPriceSet](tcg, link, len(skuIds) > 0)
+}
+
+// Retrieve prices from the given link, and, if the ids were not empty, request
+// them again when the API transiently returns no results, up to the amount of
+// times set with WithRetryOnEmpty
+func getPrices[T any](tcg *Client, link string, retry bool) ([]T, *BaseResponse, error) {
+	retries := 0
+	if retry {
+		retries = tcg.emptyRetries
 	}
 
-	var out []SKUPriceSet
-	err = json.Unmarshal(resp.Results, &out)
-	if err != nil {
-		return nil, err
+	var out []T
+	var resp *BaseResponse
+	var err error
+	for i := 0; i <= retries; i++ {
+		// Wait before asking again, as done for failed requests
+		if i > 0 {
+			wait := tcg.client.Backoff(tcg.client.RetryWaitMin, tcg.client.RetryWaitMax, i-1, nil)
+			select {
+			case <-tcg.ctx.Done():
+				return nil, nil, tcg.ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		out, resp, err = getResults[T](tcg, link)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(out) > 0 {
+			break
+		}
 	}
 
-	return out, nil
+	return out, resp, nil
 }
@@ -0,0 +1,410 @@
+package tcgplayer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Send the requests meant for the TCGplayer API to a local server instead
+type redirectTransport struct {
+	parent http.RoundTripper
+	host   string
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == "api.tcgplayer.com" {
+		req = req.Clone(req.Context())
+		req.URL.Scheme = "http"
+		req.URL.Host = t.host
+	}
+	return t.parent.RoundTrip(req)
+}
+
+// Point all the requests of the Client, tokens included, to the server
+func redirectClient(tcg *Client, server *httptest.Server) {
+	u, _ := url.Parse(server.URL)
+	transport := tcg.client.HTTPClient.Transport.(*authTransport)
+	transport.parent = &redirectTransport{
+		parent: transport.parent,
+		host:   u.Host,
+	}
+}
+
+// A local server standing in for the TCGplayer API, handing out tokens and
+// passing any other request to handler, with the API version stripped
+type stubServer struct {
+	*httptest.Server
+
+	tokenRequests atomic.Int32
+	requests      atomic.Int32
+}
+
+func newStubServer(t *testing.T, handler http.HandlerFunc) *stubServer {
+	t.Helper()
+
+	stub := &stubServer{}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			stub.tokenRequests.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"stub-token","expires_in":86400}`))
+			return
+		}
+		stub.requests.Add(1)
+		r.URL.Path = strings.TrimPrefix(r.URL.Path, "/"+tcgApiVersion)
+		handler(w, r)
+	}))
+	t.Cleanup(stub.Close)
+
+	return stub
+}
+
+// Create a Client sending all its requests to the stub server
+func (stub *stubServer) client(opts ...ClientOption) *Client {
+	tcg := NewClient("public", "private", opts...)
+	redirectClient(tcg, stub.Server)
+	return tcg
+}
+
+// Reply with a successful API envelope around results
+func writeResults(w http.ResponseWriter, total int, results interface{}) {
+	data, _ := json.Marshal(results)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(BaseResponse{
+		TotalItems: total,
+		Success:    true,
+		Errors:     []string{},
+		Results:    data,
+	})
+}
+
+func TestUnauthorizedReplayCountsAsRequest(t *testing.T) {
+	var calls atomic.Int32
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// Reject the first token as if it was revoked on the server side
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		writeResults(w, 1, []Category{{CategoryID: CategoryMagic}})
+	})
+
+	tcg := stub.client(WithDailyRequestBudget(10))
+	_, err := tcg.GetCategory(CategoryMagic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := tcg.Stats().Requests; n != 2 {
+		t.Errorf("budget counted %d requests, expected 2", n)
+	}
+	if n := stub.tokenRequests.Load(); n != 2 {
+		t.Errorf("requested %d tokens, expected 2", n)
+	}
+
+	calls.Store(0)
+	tcg = stub.client(WithDailyRequestBudget(1))
+	_, err = tcg.GetCategory(CategoryMagic)
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("expected the replay to exceed the budget, got %v", err)
+	}
+
+	// With a single token per hour, the replay cannot happen before the deadline
+	calls.Store(0)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	tcg = stub.client(WithRateLimit(rate.Every(time.Hour), 1), WithContext(ctx))
+	_, err = tcg.GetCategory(CategoryMagic)
+	if err == nil {
+		t.Error("expected the replay to wait on the rate limiter")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("sent %d requests, expected the replay to be held back", n)
+	}
+}
+
+func TestEstimateRequests(t *testing.T) {
+	totals := map[string]int{
+		"/catalog/groups":   2*MaxItemsInResponse + 1,
+		"/catalog/products": 0,
+	}
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		writeResults(w, totals[r.URL.Path], []interface{}{})
+	})
+
+	estimate, err := stub.client().EstimateRequests(CategoryMagic, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Category details, three pages of groups, and the empty page of products
+	if estimate != 5 {
+		t.Errorf("estimated %d requests, expected 5", estimate)
+	}
+}
+
+func TestTokenReused(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		writeResults(w, 1, []Category{{CategoryID: CategoryMagic}})
+	})
+
+	tcg := stub.client()
+	for i := 0; i < 2; i++ {
+		_, err := tcg.GetCategory(CategoryMagic)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := stub.tokenRequests.Load(); n != 1 {
+		t.Errorf("requested %d tokens, expected 1", n)
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	tcg := stub.client()
+	token, expires, err := tcg.client.HTTPClient.Transport.(*authTransport).requestToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "stub-token" {
+		t.Errorf("unexpected token %q", token)
+	}
+	// The stub token is valid for 86400 seconds
+	lifetime := time.Until(expires)
+	if lifetime < 24*time.Hour-time.Minute || lifetime > 24*time.Hour {
+		t.Errorf("token expires in %s, expected about 24h", lifetime)
+	}
+}
+
+func TestTokenFailureBackoff(t *testing.T) {
+	var tokenRequests, requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenRequests.Add(1)
+			http.Error(w, `{"error":"unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
+		requests.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	tcg := NewClient("public", "private")
+	tcg.client.RetryMax = 0
+	redirectClient(tcg, server)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := tcg.ListCategoryPrintings(CategoryMagic)
+			if err == nil {
+				t.Error("expected the token request to fail")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := tokenRequests.Load(); n != 1 {
+		t.Errorf("requested %d tokens, expected 1", n)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("sent %d requests without a token", n)
+	}
+}
+
+func TestListCategoryPrintings(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"totalItems": 2,
+			"success": true,
+			"errors": [],
+			"results": [
+				{"printingId": 1, "name": "Normal", "displayOrder": 1, "modifiedOn": "2014-06-25T10:50:20.957"},
+				{"printingId": 2, "name": "Foil", "displayOrder": 2, "modifiedOn": "2014-06-25T10:50:20.957"}
+			]
+		}`))
+	})
+
+	printings, err := stub.client().ListCategoryPrintings(CategoryMagic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(printings) != 2 {
+		t.Fatalf("got %d printings, expected 2", len(printings))
+	}
+	for _, printing := range printings {
+		if printing.ModifiedOn != "2014-06-25T10:50:20.957" {
+			t.Errorf("unexpected ModifiedOn %q for %s", printing.ModifiedOn, printing.Name)
+		}
+	}
+}
+
+func TestListCategoryRarities(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/catalog/categories/1/rarities" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"totalItems": 3,
+			"success": true,
+			"errors": [],
+			"results": [
+				{"rarityId": 1, "displayText": "Common", "dbValue": "C"},
+				{"rarityId": 4, "displayText": "Rare", "dbValue": "R"},
+				{"rarityId": 5, "displayText": "Mythic", "dbValue": "M"}
+			]
+		}`))
+	})
+
+	rarities, err := stub.client().ListCategoryRarities(CategoryMagic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rarities) != 3 {
+		t.Fatalf("got %d rarities, expected 3", len(rarities))
+	}
+	mythic := rarities[2]
+	if mythic.RarityId != 5 || mythic.DisplayText != "Mythic" || mythic.DbValue != "M" {
+		t.Errorf("unexpected rarity %+v", mythic)
+	}
+}
+
+func TestDecodeResponse(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/html":
+			w.Write([]byte("<html>Service maintenance</html>"))
+		case "/failed":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"success": false, "errors": ["Invalid id"], "results": [1, 2]}`))
+		case "/partial":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"success": false, "errors": [], "results": [3]}`))
+		}
+	})
+	tcg := stub.client()
+
+	var out []int
+	err := tcg.GetInto("/html", nil, &out)
+	if err == nil || !strings.Contains(err.Error(), "Service maintenance") {
+		t.Errorf("expected the body to be quoted in the error, got %v", err)
+	}
+
+	err = tcg.GetInto("/failed", nil, &out)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("expected an APIError, got %v", err)
+	}
+	if out != nil {
+		t.Errorf("results of a failed request were decoded: %v", out)
+	}
+
+	err = tcg.GetInto("/partial", nil, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 || out[0] != 3 {
+		t.Errorf("got %v, expected the results of the response", out)
+	}
+}
+
+func TestDefaultUserAgent(t *testing.T) {
+	var userAgent string
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		writeResults(w, 1, []Category{{CategoryID: CategoryMagic}})
+	})
+
+	_, err := stub.client().GetCategory(CategoryMagic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Tests are built without a module version
+	if !strings.HasPrefix(userAgent, "go-tcgplayer/devel ") {
+		t.Errorf("unexpected User-Agent %q", userAgent)
+	}
+}
+
+// Run with -race to check that one Client can be shared by many goroutines
+func TestClientConcurrentUse(t *testing.T) {
+	stub := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/pricing/sku/"):
+			writeSKUPrices(w, r)
+		case strings.HasPrefix(r.URL.Path, "/pricing/product/"):
+			writeResults(w, 1, []ProductPriceSet{{ProductId: 1, SubTypeName: "Normal"}})
+		case strings.HasSuffix(r.URL.Path, "/search/manifest"):
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"totalItems": 1, "success": true, "errors": [], "results": [{"filters": [{"name": "ProductType", "items": [{"value": "Cards"}]}]}]}`))
+		case strings.HasPrefix(r.URL.Path, "/catalog/products"):
+			writeResults(w, 1, []Product{{ProductId: 1, Name: "Black Lotus"}})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	tcg := stub.client()
+	calls := []func() error{
+		func() error {
+			_, err := tcg.GetProductsDetails([]int{1}, true)
+			return err
+		},
+		func() error {
+			_, err := tcg.ListAllProducts(CategoryMagic, nil, true, 0)
+			return err
+		},
+		func() error {
+			_, err := tcg.GetMarketPricesByProducts([]int{1})
+			return err
+		},
+		func() error {
+			_, err := tcg.GetAllMarketPricesBySKUs(sequentialIds(300))
+			return err
+		},
+		func() error {
+			_, err := tcg.DiscoverProductTypes(CategoryMagic)
+			return err
+		},
+		func() error {
+			tcg.Stats()
+			return nil
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		for _, call := range calls {
+			wg.Add(1)
+			go func(call func() error) {
+				defer wg.Done()
+				err := call()
+				if err != nil {
+					t.Error(err)
+				}
+			}(call)
+		}
+	}
+	wg.Wait()
+
+	if n := stub.tokenRequests.Load(); n != 1 {
+		t.Errorf("requested %d tokens, expected 1", n)
+	}
+}
@@ -0,0 +1,55 @@
+package tcgplayer
+
+import (
+	"errors"
+	"time"
+)
+
+// Timestamps are usually reported without timezone, and with an optional
+// fractional part, fall back to RFC3339 in case a timezone is present
+var tcgTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	time.RFC3339Nano,
+}
+
+// Returned when a timestamp is missing from the API response
+var errMissingTime = errors.New("missing timestamp")
+
+// Parse a timestamp as returned by the API, assuming UTC if no timezone is set.
+// Empty timestamps are reported as an error, so that they are never mistaken
+// for the zero time.
+func parseTCGTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errMissingTime
+	}
+
+	var err error
+	for _, layout := range tcgTimeLayouts {
+		var t time.Time
+		t, err = time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+func (g Group) ModifiedTime() (time.Time, error) {
+	return parseTCGTime(g.ModifiedOn)
+}
+
+func (p Product) ModifiedTime() (time.Time, error) {
+	return parseTCGTime(p.ModifiedOn)
+}
+
+func (g Group) PublishedTime() (time.Time, error) {
+	return parseTCGTime(g.PublishedOn)
+}
+
+func (c Category) ModifiedTime() (time.Time, error) {
+	return parseTCGTime(c.ModifiedOn)
+}
+
+func (p Printing) ModifiedTime() (time.Time, error) {
+	return parseTCGTime(p.ModifiedOn)
+}
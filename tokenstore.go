@@ -0,0 +1,83 @@
+package tcgplayer
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Persist tokens outside of the Client, see WithTokenStore.
+// Load is called whenever a new token is needed, and should return an empty
+// token without error if none was saved. Save is called with every newly
+// requested token.
+type TokenStore interface {
+	Load() (token string, expires time.Time, err error)
+	Save(token string, expires time.Time) error
+}
+
+type fileTokenStore struct {
+	path string
+}
+
+type storedToken struct {
+	Token   string    `json:"token"`
+	Expires time.Time `json:"expires"`
+}
+
+// Return a TokenStore keeping the token in a JSON file at the given path,
+// only readable by the current user, as the token grants access to the API
+func NewFileTokenStore(path string) TokenStore {
+	return &fileTokenStore{
+		path: path,
+	}
+}
+
+func (f *fileTokenStore) Load() (string, time.Time, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", time.Time{}, nil
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	var stored storedToken
+	err = json.Unmarshal(data, &stored)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return stored.Token, stored.Expires, nil
+}
+
+// Write to a temporary file first, so that a concurrent Load from another
+// process never sees a partial file
+func (f *fileTokenStore) Save(token string, expires time.Time) error {
+	data, err := json.Marshal(storedToken{
+		Token:   token,
+		Expires: expires,
+	})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.path)
+}